@@ -2,8 +2,13 @@ package main
 
 // Importação de dependências externas e internas
 import (
-	"fmt" // Pacote para formatação e impressão de textos
-	"log" // Pacote para registro de logs
+	"context"   // Pacote para cancelamento da execução
+	"fmt"       // Pacote para formatação e impressão de textos
+	"io"        // Pacote para abstração de escrita
+	"log"       // Pacote para registro de logs
+	"os"        // Pacote para manipulação de arquivos
+	"os/signal" // Pacote para captura de sinais do sistema
+	"syscall"   // Pacote com a definição de SIGTERM
 
 	// Dependências internas do projeto
 	"github.com/denner-s/gorpcstress/internal/config"  // Manipulação de configurações
@@ -12,29 +17,227 @@ import (
 	"github.com/denner-s/gorpcstress/pkg/report"       // Geração de relatórios
 )
 
+// Códigos de saída do programa. Erros de configuração e execução usam 1 (log.Fatalf).
+const (
+	exitRegression = 3   // Regressão em relação à linha de base
+	exitThresholds = 4   // Critério de aprovação não satisfeito
+	exitInterrupt  = 130 // Teste interrompido por sinal (resultados parciais)
+)
+
 // Função principal que será executada ao iniciar o programa
 func main() {
-	// Carrega a configuração do arquivo/configuração de ambiente
-	cfg := config.LoadConfig()
+	// Subcomando compare: compara dois relatórios JSON sem executar um teste
+	if len(os.Args) > 1 && os.Args[1] == "compare" {
+		os.Exit(runCompare(os.Args[2:]))
+	}
 
-	// Valida a configuração carregada. Se houver erro, encerra o programa com log
-	if err := cfg.Validate(); err != nil {
+	// Subcomando validate: verifica a configuração sem executar um teste
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(runValidate(os.Args[2:]))
+	}
+
+	// Carrega e valida a configuração das flags e do arquivo -config. Se houver erro,
+	// encerra o programa com log
+	cfg, err := config.LoadConfig(os.Args[1:])
+	if err != nil {
 		log.Fatalf("Configuração inválida: %v", err) // log.Fatalf imprime mensagem e chama os.Exit(1)
 	}
 
+	// Carrega a linha de base antes do teste para falhar cedo se ela for inválida
+	var baseline *report.Document
+	if cfg.BaselineFile != "" {
+		doc, err := readDocument(cfg.BaselineFile)
+		if err != nil {
+			log.Fatalf("Falha ao ler linha de base: %v", err)
+		}
+		baseline = &doc
+	}
+
 	// Cria um novo coletor de métricas para armazenar dados de desempenho
-	collector := metrics.NewCollector()
+	collector := metrics.NewCollector(metrics.Options{
+		Precision:  cfg.HistogramPrecision,
+		MaxLatency: cfg.HistogramMax,
+		Interval:   cfg.Interval,
+	})
 
 	// Inicializa o executor de testes de estresse com a configuração e coletor
 	stressRunner := runner.NewStressRunner(cfg, collector)
 
+	// Com relatório legível por máquina na saída padrão, as mensagens de progresso vão
+	// para a saída de erro para não corromper o documento
+	console := os.Stdout
+	if cfg.Output != config.OutputText && cfg.OutFile == "" {
+		console = os.Stderr
+	}
+
 	// Exibe informações iniciais do teste formatadas
-	fmt.Printf("Iniciando teste de estresse...\nServidor: %s\nRequisições: %d\nConcorrência: %d\n\n",
+	fmt.Fprintf(console, "Iniciando teste de estresse...\nServidor: %s\nRequisições: %d\nConcorrência: %d\n\n",
 		cfg.ServerAddress, cfg.TotalRequests, cfg.Concurrency)
+	if len(cfg.Stages) > 0 {
+		fmt.Fprintf(console, "Estágios (%s): %d\n\n", cfg.StageTarget, len(cfg.Stages))
+	} else if cfg.Rate > 0 {
+		fmt.Fprintf(console, "Taxa alvo: %.2f req/s\n\n", cfg.Rate)
+	}
+
+	// Ctrl-C ou SIGTERM interrompem o envio de novas chamadas; um segundo sinal encerra
+	// o processo imediatamente, pois a captura é desfeita após o primeiro
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	finished := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			stop()
+			fmt.Fprintf(console, "\nInterrompido: aguardando chamadas em voo por até %v (repita o sinal para encerrar imediatamente)...\n", cfg.GracePeriod)
+		case <-finished:
+		}
+	}()
 
 	// Executa efetivamente o teste de estresse
-	stressRunner.Run()
+	stressRunner.Run(ctx)
+	close(finished)
+	stop()
 
 	// Gera o relatório final com base nas métricas coletadas
-	report.GenerateReport(collector.GetMetrics())
+	m := collector.GetMetrics()
+	if err := writeOutput(cfg.OutFile, func(w io.Writer) error { return renderReport(w, cfg, m) }); err != nil {
+		log.Fatalf("Falha ao gravar relatório: %v", err)
+	}
+
+	// Grava a série temporal em CSV, se solicitado
+	if cfg.TimeSeriesFile != "" {
+		if err := writeOutput(cfg.TimeSeriesFile, func(w io.Writer) error { return report.WriteTimeSeriesCSV(w, m) }); err != nil {
+			log.Fatalf("Falha ao gravar série temporal: %v", err)
+		}
+	}
+
+	// Grava o relatório JSON para uso futuro como linha de base, se solicitado
+	if cfg.SaveFile != "" {
+		if err := writeOutput(cfg.SaveFile, func(w io.Writer) error { return report.WriteJSON(w, cfg, m) }); err != nil {
+			log.Fatalf("Falha ao gravar resultado: %v", err)
+		}
+	}
+
+	// Compara com a linha de base e sinaliza regressões pelo código de saída
+	exitCode := 0
+	if baseline != nil {
+		comparison := report.Compare(*baseline, report.NewDocument(cfg, m), cfg.Regression)
+		report.WriteComparison(console, comparison)
+		if comparison.Regressed() {
+			exitCode = exitRegression
+		}
+	}
+
+	// Critérios de aprovação não satisfeitos têm precedência sobre regressões
+	if !report.ThresholdsPassed(report.EvaluateThresholds(cfg.Thresholds, m)) {
+		exitCode = exitThresholds
+	}
+
+	// Resultados parciais nunca aprovam o teste
+	if m.Interrupted {
+		exitCode = exitInterrupt
+	}
+	os.Exit(exitCode)
+}
+
+// Função runCompare executa o subcomando compare e retorna o código de saída
+func runCompare(args []string) int {
+	cfg, err := config.LoadCompareConfig(args)
+	if err != nil {
+		log.Printf("Argumentos inválidos: %v", err)
+		return 2
+	}
+
+	baseline, err := readDocument(cfg.BaselineFile)
+	if err != nil {
+		log.Printf("Falha ao ler linha de base: %v", err)
+		return 1
+	}
+	current, err := readDocument(cfg.CurrentFile)
+	if err != nil {
+		log.Printf("Falha ao ler relatório atual: %v", err)
+		return 1
+	}
+
+	comparison := report.Compare(baseline, current, cfg.Regression)
+	report.WriteComparison(os.Stdout, comparison)
+	if comparison.Regressed() {
+		return exitRegression
+	}
+	return 0
+}
+
+// Função runValidate executa o subcomando validate e retorna o código de saída: todos os
+// problemas da configuração, do TLS, dos payloads e dos validadores são informados de
+// uma só vez
+func runValidate(args []string) int {
+	cfg, err := config.LoadConfig(args)
+	var problems config.Problems
+	if p, ok := err.(config.Problems); ok {
+		problems = append(problems, p...)
+	} else if err != nil {
+		log.Printf("Configuração inválida: %v", err)
+		return 1
+	}
+
+	if _, err := cfg.TLSConfig(); err != nil {
+		problems = append(problems, fmt.Errorf("configuração TLS inválida: %w", err))
+	}
+	if p, ok := runner.CheckMethods(cfg).(config.Problems); ok {
+		problems = append(problems, p...)
+	}
+	if len(problems) > 0 {
+		log.Printf("Configuração inválida: %v", problems)
+		return 1
+	}
+
+	fmt.Printf("Configuração válida: %s em %s, %d workers\n", cfg.MethodNames(), cfg.ServerAddress, cfg.Concurrency)
+	return 0
+}
+
+// Função readDocument lê um relatório JSON gravado por uma execução anterior
+func readDocument(path string) (report.Document, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return report.Document{}, err
+	}
+	defer func(file *os.File) {
+		if err := file.Close(); err != nil {
+			log.Printf("Erro ao fechar arquivo: %v", err)
+		}
+	}(file)
+	return report.ReadDocument(file)
+}
+
+// Função writeOutput grava o conteúdo produzido por write no arquivo informado, ou
+// na saída padrão quando nenhum arquivo é informado
+func writeOutput(path string, write func(w io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Função renderReport escreve o relatório no formato configurado
+func renderReport(w io.Writer, cfg *config.Config, m metrics.Metrics) error {
+	switch cfg.Output {
+	case config.OutputJSON:
+		return report.WriteJSON(w, cfg, m)
+	case config.OutputHTML:
+		return report.WriteHTML(w, cfg, m)
+	default:
+		report.WriteText(w, m)
+		if len(cfg.Thresholds) > 0 {
+			report.WriteThresholds(w, report.EvaluateThresholds(cfg.Thresholds, m))
+		}
+		return nil
+	}
 }
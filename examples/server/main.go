@@ -1,9 +1,15 @@
 package main
 
 import (
-	"log"     // Para registro de logs
-	"net"     // Para operações de rede TCP
-	"net/rpc" // Para implementação do servidor RPC
+	"crypto/tls"      // Para a variante com TLS
+	"flag"            // Para as opções de linha de comando
+	"log"             // Para registro de logs
+	"net"             // Para operações de rede TCP
+	"net/http"        // Para servir o RPC montado em HTTP
+	"net/rpc"         // Para implementação do servidor RPC
+	"net/rpc/jsonrpc" // Para o codec JSON-RPC 1.0
+	"os"              // Para remover sockets Unix antigos
+	"strings"         // Para reconhecer endereços unix://
 )
 
 // Args define a estrutura dos parâmetros de entrada das operações
@@ -28,7 +34,59 @@ func (t *Arithmetic) Multiply(args *Args, reply *Reply) error {
 	return nil
 }
 
+// Item define um item de pedido (exemplo de payload aninhado)
+type Item struct {
+	SKU      string  // Código do produto
+	Quantity int     // Quantidade solicitada
+	Price    float64 // Preço unitário
+}
+
+// Order define os parâmetros de um pedido, com slices, structs aninhadas e mapas
+type Order struct {
+	Customer string            // Identificação do cliente
+	Items    []Item            // Itens do pedido
+	Tags     map[string]string // Metadados livres do pedido
+}
+
+// Receipt define a resposta ao registrar um pedido
+type Receipt struct {
+	Customer string  // Cliente do pedido
+	Items    int     // Quantidade total de unidades
+	Total    float64 // Valor total do pedido
+}
+
+// Store é a estrutura que implementa o serviço de pedidos
+type Store struct{}
+
+// PlaceOrder calcula o total de um pedido via RPC
+func (s *Store) PlaceOrder(order *Order, receipt *Receipt) error {
+	log.Printf("Recebido pedido de %s com %d itens", order.Customer, len(order.Items))
+	receipt.Customer = order.Customer
+	for _, item := range order.Items {
+		receipt.Items += item.Quantity
+		receipt.Total += float64(item.Quantity) * item.Price
+	}
+	return nil
+}
+
 func main() {
+	// Opções do servidor: endereço, codec das mensagens (gob ou jsonrpc), montagem em HTTP e TLS
+	addr := flag.String("addr", "127.0.0.1:1234", "Endereço de escuta: host:porta ou unix:///caminho/para.sock")
+	codec := flag.String("codec", "gob", "Codec das mensagens: gob ou jsonrpc")
+	httpPath := flag.String("http", "", "Monta o RPC em HTTP no caminho informado (ex: /_goRPC_), como rpc.HandleHTTP")
+	tlsDir := flag.String("tls-dir", "", "Habilita TLS com certificados autoassinados gerados neste diretório")
+	mutual := flag.Bool("mtls", false, "Exige certificado do cliente emitido pela CA gerada (com -tls-dir)")
+	flag.Parse()
+	if *mutual && *tlsDir == "" {
+		log.Fatal("-mtls exige -tls-dir")
+	}
+	if *codec != "gob" && *codec != "jsonrpc" {
+		log.Fatalf("Codec desconhecido: %s", *codec)
+	}
+	if *httpPath != "" && *codec != "gob" {
+		log.Fatal("O RPC montado em HTTP usa apenas o codec gob")
+	}
+
 	// 1. Criação e registro do serviço RPC
 	arithService := new(Arithmetic)
 
@@ -39,9 +97,19 @@ func main() {
 		log.Fatal("Falha ao registrar o serviço RPC:", err)
 	}
 
-	// 2. Configuração do listener TCP
-	// Usamos 127.0.0.1 explicitamente para forçar IPv4
-	listener, err := net.Listen("tcp", "127.0.0.1:1234")
+	// Registra o serviço de pedidos, usado com payloads estruturados
+	if err := rpc.RegisterName("Store", new(Store)); err != nil {
+		log.Fatal("Falha ao registrar o serviço RPC:", err)
+	}
+
+	// 2. Configuração do listener TCP ou Unix
+	// O endereço padrão usa 127.0.0.1 explicitamente para forçar IPv4
+	network, address := "tcp", *addr
+	if path, ok := strings.CutPrefix(*addr, "unix://"); ok {
+		network, address = "unix", path
+		_ = os.Remove(path) // Remove o socket deixado por uma execução anterior
+	}
+	listener, err := net.Listen(network, address)
 	if err != nil {
 		log.Fatal("Erro ao iniciar listener:", err)
 	}
@@ -52,7 +120,29 @@ func main() {
 		}
 	}(listener) // Garante o fechamento adequado ao final
 
-	log.Println("✅ Servidor RPC iniciado em 127.0.0.1:1234")
+	// Variante com TLS: os certificados gerados permitem testar localmente com
+	// -tls -tls-ca=<dir>/ca.pem (e -tls-cert/-tls-key com -mtls)
+	if *tlsDir != "" {
+		config, err := generateCertificates(*tlsDir, *mutual)
+		if err != nil {
+			log.Fatal("Falha ao gerar certificados:", err)
+		}
+		listener = tls.NewListener(listener, config)
+		log.Printf("🔒 TLS habilitado (mútuo: %v); certificados em %s", *mutual, *tlsDir)
+	}
+
+	// Variante montada em HTTP: os clientes fazem o handshake CONNECT no caminho
+	// informado antes das chamadas (rpc.DialHTTP / rpc.DialHTTPPath)
+	if *httpPath != "" {
+		rpc.HandleHTTP()
+		if *httpPath != rpc.DefaultRPCPath {
+			http.Handle(*httpPath, rpc.DefaultServer)
+		}
+		log.Printf("✅ Servidor RPC (gob sobre HTTP, caminho %s) iniciado em %s", *httpPath, *addr)
+		log.Fatal(http.Serve(listener, nil))
+	}
+
+	log.Printf("✅ Servidor RPC (%s) iniciado em %s", *codec, *addr)
 
 	// 3. Loop principal de aceitação de conexões
 	for {
@@ -73,8 +163,12 @@ func main() {
 			}(conn) // Garante fechamento da conexão
 			log.Printf("🔌 Nova conexão de %s", conn.RemoteAddr())
 
-			// Serve a conexão usando o pacote RPC
-			rpc.ServeConn(conn)
+			// Serve a conexão usando o pacote RPC com o codec escolhido
+			if *codec == "jsonrpc" {
+				jsonrpc.ServeConn(conn)
+			} else {
+				rpc.ServeConn(conn)
+			}
 		}(conn)
 	}
 }
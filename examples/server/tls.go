@@ -0,0 +1,138 @@
+package main
+
+import (
+	"crypto/ecdsa"     // Para as chaves dos certificados
+	"crypto/elliptic"  // Para a curva P-256
+	"crypto/rand"      // Para números de série e assinaturas
+	"crypto/tls"       // Para a configuração TLS do servidor
+	"crypto/x509"      // Para a emissão dos certificados
+	"crypto/x509/pkix" // Para os nomes dos certificados
+	"encoding/pem"     // Para gravar certificados e chaves em PEM
+	"math/big"         // Para os números de série
+	"net"              // Para os endereços IP dos certificados
+	"os"               // Para gravar os arquivos
+	"path/filepath"    // Para montar os caminhos dos arquivos
+	"time"             // Para a validade dos certificados
+)
+
+// Arquivos gravados por generateCertificates no diretório informado em -tls-dir
+const (
+	caFile        = "ca.pem"         // CA autoassinada que emite os demais certificados
+	serverFile    = "server.pem"     // Certificado do servidor (localhost, 127.0.0.1, ::1)
+	serverKeyFile = "server-key.pem" // Chave do servidor
+	clientFile    = "client.pem"     // Certificado do cliente para TLS mútuo
+	clientKeyFile = "client-key.pem" // Chave do cliente
+)
+
+// certificate agrupa um certificado emitido e sua chave privada
+type certificate struct {
+	cert *x509.Certificate
+	der  []byte
+	key  *ecdsa.PrivateKey
+}
+
+// generateCertificates gera uma CA autoassinada e, a partir dela, os certificados do
+// servidor e do cliente para testes locais, gravando-os em dir. A configuração
+// devolvida exige certificado do cliente emitido pela CA quando mutual é verdadeiro.
+func generateCertificates(dir string, mutual bool) (*tls.Config, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	// 1. CA que assina os certificados do servidor e do cliente
+	ca, err := issue(&x509.Certificate{
+		Subject:               pkix.Name{CommonName: "gorpcstress CA local"},
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Certificado do servidor, válido para os endereços locais
+	server, err := issue(&x509.Certificate{
+		Subject:     pkix.Name{CommonName: "localhost"},
+		DNSNames:    []string{"localhost"},
+		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Certificado do cliente, usado com -mtls
+	client, err := issue(&x509.Certificate{
+		Subject:     pkix.Name{CommonName: "gorpcstress"},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Grava os arquivos usados pelo cliente (-tls-ca, -tls-cert e -tls-key)
+	for name, c := range map[string]*certificate{caFile: ca, serverFile: server, clientFile: client} {
+		if err := writePEM(filepath.Join(dir, name), "CERTIFICATE", c.der); err != nil {
+			return nil, err
+		}
+	}
+	for name, c := range map[string]*certificate{serverKeyFile: server, clientKeyFile: client} {
+		der, err := x509.MarshalECPrivateKey(c.key)
+		if err != nil {
+			return nil, err
+		}
+		if err := writePEM(filepath.Join(dir, name), "EC PRIVATE KEY", der); err != nil {
+			return nil, err
+		}
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{server.der}, PrivateKey: server.key}},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if mutual {
+		pool := x509.NewCertPool()
+		pool.AddCert(ca.cert)
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// issue emite um certificado a partir do modelo, assinado por parent ou autoassinado
+// quando parent é nil
+func issue(template *x509.Certificate, parent *certificate) (*certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	template.SerialNumber = serial
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(365 * 24 * time.Hour)
+
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &certificate{cert: cert, der: der, key: key}, nil
+}
+
+// writePEM grava um bloco PEM com permissões restritas
+func writePEM(path, blockType string, der []byte) error {
+	return os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600)
+}
@@ -2,64 +2,321 @@ package config
 
 // Importação de pacotes necessários.
 import (
-	"flag" // Pacote para manipulação de flags de linha de comando.
-	"fmt"  // Pacote para formatação de strings e mensagens de erro.
-	"time" // Pacote para manipulação de tempo e durações.
+	"flag"         // Pacote para manipulação de flags de linha de comando.
+	"fmt"          // Pacote para formatação de strings e mensagens de erro.
+	"math/rand/v2" // Pacote para sortear a semente quando -seed não é informada.
+	"net/http"     // Pacote com o tipo dos cabeçalhos HTTP.
+	"net/rpc"      // Pacote com o caminho padrão do net/rpc sobre HTTP.
+	"strings"      // Pacote para manipulação de strings.
+	"time"         // Pacote para manipulação de tempo e durações.
+
+	// Dependências internas do projeto.
+	"github.com/denner-s/gorpcstress/internal/metrics" // Limites dos histogramas de latência.
+	"github.com/denner-s/gorpcstress/pkg/rpcclient"    // Codecs suportados pelo cliente RPC.
+)
+
+// Formatos de saída do relatório.
+const (
+	OutputText = "text" // Relatório legível em texto.
+	OutputJSON = "json" // Relatório JSON com esquema versionado.
+	OutputHTML = "html" // Relatório HTML autocontido com gráficos.
+)
+
+// Estratégias de conexão aceitas em -conn-mode.
+const (
+	ConnModeWorker  = "worker"  // Cada worker mantém sua própria conexão persistente.
+	ConnModePool    = "pool"    // Os workers compartilham um pool de -pool-size conexões multiplexadas.
+	ConnModeRequest = "request" // Cada chamada abre e fecha sua própria conexão.
 )
 
 // Estrutura Config armazena todas as configurações necessárias para o teste de estresse.
 type Config struct {
-	ServerAddress string        // Endereço do servidor RPC (ex: "localhost:1234").
+	ServerAddress string        // Endereço do servidor RPC (ex: "localhost:1234", "unix:///run/app.sock").
 	TotalRequests int           // Número total de requisições a serem enviadas.
 	Concurrency   int           // Número de workers concorrentes (goroutines).
 	RPCMethod     string        // Método RPC a ser chamado (ex: "Arithmetic.Multiply").
+	MethodMix     []MethodSpec  // Mistura ponderada de métodos (opcional, sobrescreve RPCMethod).
+	Seed          int64         // Semente dos valores sorteados (payloads e métodos); sorteada quando não informada.
+	Codec         string        // Codec das mensagens: "gob" ou "jsonrpc".
+	Headers       http.Header   // Cabeçalhos HTTP enviados em cada requisição (transporte HTTP).
+	BatchSize     int           // Requisições JSON-RPC 2.0 enviadas em cada lote (transporte HTTP).
+	HTTPConnect   bool          // Usa net/rpc sobre HTTP (handshake CONNECT, servidores com rpc.HandleHTTP).
+	RPCPath       string        // Caminho do handshake HTTP CONNECT (ex: "/_goRPC_").
+	ConnMode      string        // Estratégia de conexão: "worker", "pool" ou "request".
+	PoolSize      int           // Número de conexões compartilhadas no modo "pool".
+	Pipeline      int           // Máximo de chamadas em voo por conexão (1 desativa o pipelining).
+	TLS           bool          // Usa TLS nas conexões TCP.
+	TLSCAFile     string        // CA usada para verificar o servidor (padrão: autoridades do sistema).
+	TLSCertFile   string        // Certificado do cliente para TLS mútuo (opcional).
+	TLSKeyFile    string        // Chave privada do certificado do cliente.
+	TLSServerName string        // Nome verificado no certificado do servidor (padrão: host do endereço).
+	TLSInsecure   bool          // Desativa a verificação do certificado do servidor.
+	TLSMinVersion string        // Versão mínima de TLS: "1.0", "1.1", "1.2" ou "1.3".
 	Timeout       time.Duration // Timeout para as conexões com o servidor.
+	GracePeriod   time.Duration // Prazo para concluir as chamadas em voo após uma interrupção.
 	Duration      time.Duration // Duração total do teste (opcional, sobrescreve TotalRequests).
 	PayloadFile   string        // Caminho para um arquivo JSON com payload customizado (opcional).
+	Rate          float64       // Taxa alvo em requisições por segundo (modo malha aberta, opcional).
+	Stages        []Stage       // Perfil de carga em estágios (opcional, sobrescreve Rate e Duration).
+	StageTarget   string        // O que os estágios controlam: "rate" ou "concurrency".
+	ConfigFile    string        // Arquivo YAML ou JSON com a configuração (opcional).
+
+	Reconnect       bool          // Restabelece conexões perdidas (connection is shut down, EOF).
+	DialRetries     int           // Novas tentativas de conexão após uma falha.
+	Retries         int           // Novas tentativas de chamadas que falham por perda de conexão (métodos idempotentes).
+	RetryBackoff    time.Duration // Espera base entre tentativas, dobrada a cada nova tentativa.
+	RetryMaxBackoff time.Duration // Espera máxima entre tentativas.
+
+	HistogramPrecision int           // Algarismos significativos dos histogramas de latência (1 a 5).
+	HistogramMax       time.Duration // Maior latência rastreável pelos histogramas.
+	Interval           time.Duration // Largura dos intervalos da série temporal (zero desabilita).
+	TimeSeriesFile     string        // Caminho do arquivo CSV da série temporal (opcional).
+
+	Output  string // Formato do relatório: "text", "json" ou "html".
+	OutFile string // Arquivo onde o relatório é gravado (padrão: saída padrão).
+
+	SaveFile     string                // Arquivo onde o relatório JSON é gravado, além do relatório principal.
+	BaselineFile string                // Relatório JSON de uma execução anterior para comparação (opcional).
+	Regression   []RegressionThreshold // Limites de regressão em relação à linha de base.
+
+	Thresholds []Threshold // Critérios absolutos de aprovação do teste (opcional).
+
+	Validators []ValidatorSpec // Validadores das respostas (padrão: compara com "expected" do payload).
 }
 
-// Função LoadConfig carrega as configurações a partir de flags de linha de comando.
-func LoadConfig() *Config {
+// Função LoadConfig carrega as configurações a partir dos argumentos de linha de comando
+// e, com -config, do arquivo YAML ou JSON informado, cujos valores são sobrescritos pelas
+// flags. A configuração é validada, e o erro devolvido reúne todos os problemas
+// encontrados no arquivo e na validação.
+func LoadConfig(args []string) (*Config, error) {
 	// Cria uma nova instância da estrutura Config.
-	cfg := &Config{}
+	cfg := &Config{Headers: make(http.Header)}
+	cfg.Regression, _ = ParseRegressionThresholds(DefaultRegression)
 
 	// Define as flags de linha de comando e as associa aos campos da estrutura Config.
-	flag.StringVar(&cfg.ServerAddress, "server", "localhost:1234", "Endereço do servidor RPC")
-	flag.IntVar(&cfg.TotalRequests, "requests", 1000, "Número total de requisições")
-	flag.IntVar(&cfg.Concurrency, "concurrency", 50, "Número de workers concorrentes")
-	flag.StringVar(&cfg.RPCMethod, "method", "Arithmetic.Multiply", "Método RPC a ser chamado")
-	flag.DurationVar(&cfg.Timeout, "timeout", 30*time.Second, "Timeout das conexões")
-	flag.DurationVar(&cfg.Duration, "duration", 0, "Duração do teste (sobrescreve requests)")
-	flag.StringVar(&cfg.PayloadFile, "payload", "", "Arquivo JSON com payload customizado")
+	fs := flag.NewFlagSet("gorpcstress", flag.ExitOnError)
+	fs.StringVar(&cfg.ConfigFile, "config", "", "Arquivo YAML ou JSON com a configuração do teste (as flags têm precedência)")
+	fs.StringVar(&cfg.ServerAddress, "server", "localhost:1234", "Endereço do servidor RPC")
+	fs.IntVar(&cfg.TotalRequests, "requests", 1000, "Número total de requisições")
+	fs.IntVar(&cfg.Concurrency, "concurrency", 50, "Número de workers concorrentes")
+	fs.StringVar(&cfg.RPCMethod, "method", "Arithmetic.Multiply", "Método RPC a ser chamado")
+	fs.Var(methodsFlag{&cfg.MethodMix}, "methods", "Mistura ponderada de métodos Método:peso[:payload] (ex: Users.Get:70:get.json,Users.Update:20:update.json); sobrescreve -method")
+	fs.StringVar(&cfg.Codec, "codec", rpcclient.CodecGob, "Codec das mensagens: gob ou jsonrpc (JSON-RPC 1.0)")
+	fs.Var(headersFlag{cfg.Headers}, "header", "Cabeçalho HTTP \"Nome: valor\" enviado em cada requisição; pode ser repetida")
+	fs.IntVar(&cfg.BatchSize, "batch", 1, "Requisições JSON-RPC 2.0 por lote no transporte HTTP; cada lote conta como uma requisição nas métricas")
+	fs.BoolVar(&cfg.HTTPConnect, "http-connect", false, "Usa net/rpc sobre HTTP: handshake CONNECT antes das chamadas (servidores com rpc.HandleHTTP)")
+	fs.StringVar(&cfg.RPCPath, "rpc-path", rpc.DefaultRPCPath, "Caminho do handshake HTTP CONNECT (com -http-connect)")
+	fs.StringVar(&cfg.ConnMode, "conn-mode", ConnModeWorker, "Estratégia de conexão: worker (uma por worker), pool (compartilhadas) ou request (uma por chamada)")
+	fs.IntVar(&cfg.PoolSize, "pool-size", 4, "Número de conexões compartilhadas com -conn-mode=pool")
+	fs.IntVar(&cfg.Pipeline, "pipeline", 1, "Chamadas assíncronas em voo por worker (rpc.Client.Go); 1 desativa")
+	fs.BoolVar(&cfg.TLS, "tls", false, "Usa TLS nas conexões com o servidor (URLs https:// usam TLS sempre)")
+	fs.StringVar(&cfg.TLSCAFile, "tls-ca", "", "Arquivo PEM com a CA que assina o certificado do servidor")
+	fs.StringVar(&cfg.TLSCertFile, "tls-cert", "", "Arquivo PEM com o certificado do cliente (TLS mútuo)")
+	fs.StringVar(&cfg.TLSKeyFile, "tls-key", "", "Arquivo PEM com a chave privada do certificado do cliente")
+	fs.StringVar(&cfg.TLSServerName, "tls-server-name", "", "Nome verificado no certificado do servidor (padrão: host do endereço)")
+	fs.BoolVar(&cfg.TLSInsecure, "tls-insecure", false, "Não verifica o certificado do servidor (apenas para testes)")
+	fs.StringVar(&cfg.TLSMinVersion, "tls-min-version", "1.2", "Versão mínima de TLS: 1.0, 1.1, 1.2 ou 1.3")
+	fs.DurationVar(&cfg.Timeout, "timeout", 30*time.Second, "Timeout das conexões")
+	fs.BoolVar(&cfg.Reconnect, "reconnect", true, "Restabelece conexões perdidas (connection is shut down, EOF) na chamada seguinte")
+	fs.IntVar(&cfg.DialRetries, "dial-retries", 3, "Novas tentativas de conexão após uma falha, com backoff exponencial")
+	fs.IntVar(&cfg.Retries, "retries", 0, "Novas tentativas de chamadas que falham por perda de conexão (apenas métodos idempotentes)")
+	fs.DurationVar(&cfg.RetryBackoff, "retry-backoff", 50*time.Millisecond, "Espera base entre tentativas, dobrada a cada nova tentativa (com jitter)")
+	fs.DurationVar(&cfg.RetryMaxBackoff, "retry-max-backoff", 2*time.Second, "Espera máxima entre tentativas")
+	fs.DurationVar(&cfg.GracePeriod, "grace", 5*time.Second, "Prazo para concluir as chamadas em voo após Ctrl-C/SIGTERM")
+	fs.DurationVar(&cfg.Duration, "duration", 0, "Duração do teste (sobrescreve requests)")
+	fs.StringVar(&cfg.PayloadFile, "payload", "", "Arquivo JSON com payload customizado")
+	fs.Int64Var(&cfg.Seed, "seed", 0, "Semente dos geradores dos payloads e do sorteio de métodos, para execuções reprodutíveis (padrão: sorteada)")
+	fs.Float64Var(&cfg.Rate, "rate", 0, "Taxa alvo em req/s (malha aberta; -concurrency limita as chamadas em voo)")
+	fs.Var(stagesFlag{&cfg.Stages}, "stages", "Estágios do perfil de carga (ex: 30s:500,2m:500,10s:2000,30s:0)")
+	fs.StringVar(&cfg.StageTarget, "stage-target", StageTargetRate, "Alvo dos estágios: rate ou concurrency")
+	fs.IntVar(&cfg.HistogramPrecision, "hdr-precision", 3, "Algarismos significativos dos histogramas de latência (1 a 5)")
+	fs.DurationVar(&cfg.HistogramMax, "hdr-max", time.Hour, "Maior latência rastreável pelos histogramas")
+	fs.DurationVar(&cfg.Interval, "interval", time.Second, "Intervalo da série temporal (0 desabilita)")
+	fs.StringVar(&cfg.TimeSeriesFile, "timeseries-out", "", "Arquivo CSV para gravar a série temporal")
+	fs.StringVar(&cfg.Output, "output", OutputText, "Formato do relatório: text, json ou html")
+	fs.StringVar(&cfg.OutFile, "out", "", "Arquivo onde o relatório é gravado (padrão: saída padrão)")
+	fs.StringVar(&cfg.SaveFile, "save", "", "Arquivo onde o relatório JSON é gravado, além do relatório principal")
+	fs.StringVar(&cfg.BaselineFile, "baseline", "", "Relatório JSON de uma execução anterior para comparação")
+	fs.Var(regressionFlag{&cfg.Regression}, "regression", "Limites de regressão em relação à linha de base, relativos em % e em pontos percentuais para error_rate (ex: p99=10%,rps=5%,error_rate=0.5%)")
+	fs.Var(validatorsFlag{&cfg.Validators}, "validate", "Validador de respostas [Método:]tipo (ex: \"field:Result=15\", \"range:Total=0..100\", none); pode ser repetida")
+	fs.Var(thresholdsFlag{&cfg.Thresholds}, "threshold", "Critério de aprovação (ex: \"p99 < 50ms\", \"error_rate < 0.1%\", \"rps >= 1000\"); pode ser repetida")
 
 	// Processa as flags fornecidas na linha de comando.
-	flag.Parse()
+	_ = fs.Parse(args) // ExitOnError: flags inválidas encerram o programa
 
-	// Retorna a estrutura Config preenchida com os valores das flags.
-	return cfg
+	// Aplica o arquivo de configuração às flags não informadas na linha de comando.
+	var problems Problems
+	if cfg.ConfigFile != "" {
+		err := loadFile(fs, cfg.ConfigFile)
+		if p, ok := err.(Problems); ok {
+			problems = append(problems, p...)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	// Sem -seed na linha de comando ou no arquivo a semente é sorteada e registrada na
+	// configuração, para que o relatório permita repetir a execução. Qualquer valor
+	// informado, inclusive zero, é respeitado.
+	seedSet := false
+	fs.Visit(func(f *flag.Flag) { seedSet = seedSet || f.Name == "seed" })
+	if !seedSet {
+		cfg.Seed = rand.Int64()
+	}
+
+	// Retorna a estrutura Config preenchida, com todos os problemas encontrados.
+	if err := cfg.Validate(); err != nil {
+		problems = append(problems, err.(Problems)...)
+	}
+	return cfg, problems.Err()
 }
 
-// Método Validate verifica se as configurações carregadas são válidas.
+// Método Validate verifica se as configurações carregadas são válidas. O erro devolvido
+// é do tipo Problems e reúne todos os problemas encontrados.
 func (c *Config) Validate() error {
+	var problems Problems
+
 	// Verifica se o número de requisições é maior que zero.
 	if c.TotalRequests < 1 {
-		return fmt.Errorf("número de requisições deve ser maior que zero")
+		problems = append(problems, fmt.Errorf("número de requisições deve ser maior que zero"))
 	}
 
 	// Verifica se o número de workers concorrentes é maior que zero.
 	if c.Concurrency < 1 {
-		return fmt.Errorf("concorrência deve ser maior que zero")
+		problems = append(problems, fmt.Errorf("concorrência deve ser maior que zero"))
 	}
 
 	// Verifica se o endereço do servidor foi fornecido.
 	if c.ServerAddress == "" {
-		return fmt.Errorf("endereço do servidor não pode estar vazio")
+		problems = append(problems, fmt.Errorf("endereço do servidor não pode estar vazio"))
+	}
+
+	// Verifica o esquema do endereço: URLs http(s):// usam o transporte HTTP e os
+	// demais endereços devem ser host:porta, tcp://host:porta ou unix:///caminho.
+	if c.ServerAddress != "" && !rpcclient.IsHTTP(c.ServerAddress) {
+		if _, err := rpcclient.ParseTarget(c.ServerAddress); err != nil {
+			problems = append(problems, err)
+		}
 	}
 
 	if c.RPCMethod == "" {
-		return fmt.Errorf("método RPC não pode ser vazio")
+		problems = append(problems, fmt.Errorf("método RPC não pode ser vazio"))
+	}
+
+	// Verifica a mistura de métodos, quando informada.
+	if err := validateMethods(c.MethodMix); err != nil {
+		problems = append(problems, err)
+	}
+
+	// Verifica o codec das mensagens.
+	if c.Codec != rpcclient.CodecGob && c.Codec != rpcclient.CodecJSONRPC {
+		problems = append(problems, fmt.Errorf("codec deve ser %q ou %q", rpcclient.CodecGob, rpcclient.CodecJSONRPC))
+	}
+
+	// Verifica as opções do transporte HTTP.
+	if c.BatchSize < 1 {
+		problems = append(problems, fmt.Errorf("tamanho do lote deve ser maior que zero"))
+	}
+	if !rpcclient.IsHTTP(c.ServerAddress) && (c.BatchSize > 1 || len(c.Headers) > 0) {
+		problems = append(problems, fmt.Errorf("-batch e -header exigem um servidor http:// ou https://"))
+	}
+
+	// Verifica as opções do net/rpc sobre HTTP.
+	if c.HTTPConnect {
+		if rpcclient.IsHTTP(c.ServerAddress) {
+			problems = append(problems, fmt.Errorf("-http-connect usa o endereço host:porta, não uma URL http://"))
+		}
+		if c.Codec != rpcclient.CodecGob {
+			problems = append(problems, fmt.Errorf("-http-connect exige o codec %q (rpc.HandleHTTP serve apenas gob)", rpcclient.CodecGob))
+		}
+		if !strings.HasPrefix(c.RPCPath, "/") {
+			problems = append(problems, fmt.Errorf("caminho do handshake HTTP deve começar com /"))
+		}
+	}
+
+	// Verifica a estratégia de conexão.
+	switch c.ConnMode {
+	case ConnModeWorker, ConnModePool, ConnModeRequest:
+	default:
+		problems = append(problems, fmt.Errorf("estratégia de conexão deve ser %q, %q ou %q", ConnModeWorker, ConnModePool, ConnModeRequest))
+	}
+	if c.PoolSize < 1 {
+		problems = append(problems, fmt.Errorf("tamanho do pool de conexões deve ser maior que zero"))
+	}
+
+	// Verifica o pipelining: exige o transporte net/rpc, conexões persistentes e um modo
+	// com workers persistentes (número de requisições, -rate ou estágios de taxa).
+	if c.Pipeline < 1 {
+		problems = append(problems, fmt.Errorf("pipeline deve ser maior que zero"))
+	}
+	if c.Pipeline > 1 {
+		switch {
+		case rpcclient.IsHTTP(c.ServerAddress):
+			problems = append(problems, fmt.Errorf("-pipeline exige o transporte net/rpc (use -batch com servidores http://)"))
+		case c.ConnMode == ConnModeRequest:
+			problems = append(problems, fmt.Errorf("-pipeline exige conexões persistentes (-conn-mode worker ou pool)"))
+		case len(c.Stages) > 0 && c.StageTarget == StageTargetConcurrency:
+			problems = append(problems, fmt.Errorf("-pipeline não se aplica a estágios de concorrência"))
+		case len(c.Stages) == 0 && c.Rate == 0 && c.Duration > 0:
+			problems = append(problems, fmt.Errorf("-pipeline com -duration exige -rate"))
+		}
+	}
+
+	// Verifica as opções de TLS.
+	if err := c.validateTLS(); err != nil {
+		problems = append(problems, err)
+	}
+
+	// Verifica a política de novas tentativas. Com pipelining as chamadas em voo na
+	// conexão perdida não são repetidas.
+	if c.DialRetries < 0 || c.Retries < 0 {
+		problems = append(problems, fmt.Errorf("número de novas tentativas não pode ser negativo"))
+	}
+	if c.RetryBackoff <= 0 || c.RetryMaxBackoff < c.RetryBackoff {
+		problems = append(problems, fmt.Errorf("-retry-backoff deve ser positivo e não maior que -retry-max-backoff"))
+	}
+	if c.Retries > 0 && c.Pipeline > 1 {
+		problems = append(problems, fmt.Errorf("-retries não se aplica a -pipeline"))
+	}
+
+	// Verifica o prazo de interrupção.
+	if c.GracePeriod < 0 {
+		problems = append(problems, fmt.Errorf("prazo de interrupção não pode ser negativo"))
+	}
+
+	// Verifica se a taxa alvo, quando informada, é positiva.
+	if c.Rate < 0 {
+		problems = append(problems, fmt.Errorf("taxa alvo não pode ser negativa"))
+	}
+
+	// Verifica o perfil de carga em estágios, quando informado.
+	if len(c.Stages) > 0 {
+		if err := validateStages(c.Stages, c.StageTarget); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	// Verifica a configuração dos histogramas de latência.
+	if c.HistogramPrecision < metrics.MinPrecision || c.HistogramPrecision > metrics.MaxPrecision {
+		problems = append(problems, fmt.Errorf("precisão do histograma deve estar entre %d e %d", metrics.MinPrecision, metrics.MaxPrecision))
+	}
+	if c.HistogramMax < time.Millisecond {
+		problems = append(problems, fmt.Errorf("latência máxima do histograma deve ser de pelo menos 1ms"))
+	}
+
+	// Verifica a série temporal.
+	if c.Interval < 0 {
+		problems = append(problems, fmt.Errorf("intervalo da série temporal não pode ser negativo"))
+	}
+	if c.TimeSeriesFile != "" && c.Interval == 0 {
+		problems = append(problems, fmt.Errorf("-timeseries-out exige um intervalo de série temporal maior que zero"))
+	}
+
+	// Verifica o formato do relatório.
+	if c.Output != OutputText && c.Output != OutputJSON && c.Output != OutputHTML {
+		problems = append(problems, fmt.Errorf("formato de saída deve ser %q, %q ou %q", OutputText, OutputJSON, OutputHTML))
 	}
 
 	// Retorna nil se todas as validações forem bem-sucedidas.
-	return nil
+	return problems.Err()
 }
@@ -0,0 +1,308 @@
+package config
+
+// Importação de pacotes necessários.
+import (
+	"flag"    // Pacote com as flags que recebem os valores do arquivo.
+	"fmt"     // Pacote para formatação de strings e mensagens de erro.
+	"os"      // Pacote para leitura do arquivo e das variáveis de ambiente.
+	"reflect" // Pacote para listar os campos aceitos nos objetos das listas.
+	"strconv" // Pacote para conversão dos pesos dos métodos.
+	"strings" // Pacote para manipulação de strings.
+
+	// Dependência externa.
+	"gopkg.in/yaml.v3" // Interpretação de arquivos YAML (e JSON, um subconjunto de YAML).
+)
+
+// repeatableFlags lista as flags que podem ser repetidas na linha de comando. No arquivo
+// de configuração elas recebem uma lista, com um valor por elemento; as demais flags
+// que recebem uma lista (ex: stages, methods, regression) a interpretam unida por vírgulas.
+var repeatableFlags = map[string]bool{"header": true, "validate": true, "threshold": true}
+
+// Tipo Problems reúne todos os problemas encontrados na configuração, para que sejam
+// informados de uma só vez.
+type Problems []error
+
+// Método Error lista os problemas, um por linha.
+func (p Problems) Error() string {
+	if len(p) == 1 {
+		return p[0].Error()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d problemas encontrados:", len(p))
+	for _, err := range p {
+		b.WriteString("\n  - " + err.Error())
+	}
+	return b.String()
+}
+
+// Método Err devolve os problemas como erro, ou nil se não houver nenhum.
+func (p Problems) Err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return p
+}
+
+// Função loadFile aplica às flags os valores do arquivo de configuração YAML ou JSON.
+// As chaves do arquivo são os nomes das flags; flags informadas na linha de comando
+// têm precedência e seus valores no arquivo são ignorados. Referências ${VAR} e
+// ${VAR:-padrão} nos valores são substituídas pelas variáveis de ambiente.
+func loadFile(fs *flag.FlagSet, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("falha ao ler arquivo de configuração: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil // Arquivo vazio
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: a configuração deve ser um objeto com os nomes das flags como chaves", path)
+	}
+
+	// Flags da linha de comando têm precedência sobre o arquivo
+	fromArgs := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { fromArgs[f.Name] = true })
+
+	var problems Problems
+	seen := make(map[string]bool)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		name := key.Value
+		switch {
+		case name == "config" || fs.Lookup(name) == nil:
+			problems = append(problems, fmt.Errorf("%s:%d: campo desconhecido %q", path, key.Line, name))
+		case seen[name]:
+			problems = append(problems, fmt.Errorf("%s:%d: campo %q repetido", path, key.Line, name))
+		case fromArgs[name]:
+			// Valor sobrescrito pela flag
+		default:
+			for _, err := range applyFileValue(fs, name, value, fromArgs) {
+				problems = append(problems, fmt.Errorf("%s:%d: %s: %w", path, value.Line, name, err))
+			}
+		}
+		seen[name] = true
+	}
+	return problems.Err()
+}
+
+// Função applyFileValue atribui à flag o valor de um campo do arquivo: valores simples
+// diretamente, listas elemento a elemento (flags repetíveis) ou unidas por vírgulas, e
+// os cabeçalhos HTTP como um objeto "Nome: valor". A lista de métodos da mistura é
+// tratada por applyMethods.
+func applyFileValue(fs *flag.FlagSet, name string, value *yaml.Node, fromArgs map[string]bool) []error {
+	var values []string
+	var errs []error
+	switch value.Kind {
+	case yaml.ScalarNode:
+		values = []string{value.Value}
+	case yaml.SequenceNode:
+		if name == "methods" {
+			return applyMethods(fs, value, fromArgs)
+		}
+		for _, item := range value.Content {
+			v, err := fileListItem(name, item)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			values = append(values, v)
+		}
+		if !repeatableFlags[name] {
+			values = []string{strings.Join(values, ",")}
+		}
+	case yaml.MappingNode:
+		if name != "header" {
+			return []error{fmt.Errorf("não aceita um objeto")}
+		}
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			values = append(values, value.Content[i].Value+": "+value.Content[i+1].Value)
+		}
+	default:
+		return []error{fmt.Errorf("valor não suportado")}
+	}
+
+	for _, v := range values {
+		if err := setExpanded(fs, name, v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Função setExpanded atribui à flag o valor com as variáveis de ambiente substituídas.
+func setExpanded(fs *flag.FlagSet, name, value string) error {
+	expanded, err := expandEnv(value)
+	if err != nil {
+		return err
+	}
+	return fs.Set(name, expanded)
+}
+
+// Função fileListItem converte um elemento de lista em texto. Além de valores simples,
+// aceita os estágios como objetos {duration, target}.
+func fileListItem(name string, item *yaml.Node) (string, error) {
+	switch {
+	case item.Kind == yaml.ScalarNode:
+		return item.Value, nil
+	case item.Kind == yaml.MappingNode && name == "stages":
+		var stage struct {
+			Duration string  `yaml:"duration"`
+			Target   *string `yaml:"target"`
+		}
+		if err := decodeObject(item, &stage); err != nil {
+			return "", err
+		}
+		if stage.Duration == "" || stage.Target == nil {
+			return "", fmt.Errorf("estágios devem ter duration e target")
+		}
+		return stage.Duration + ":" + *stage.Target, nil
+	case item.Kind == yaml.MappingNode:
+		return "", fmt.Errorf("não aceita objetos na lista")
+	default:
+		return "", fmt.Errorf("listas aninhadas não são suportadas")
+	}
+}
+
+// Função applyMethods atribui a -methods a mistura declarada como lista no arquivo. Os
+// métodos em texto seguem o formato da flag; os objetos {method, weight, payload,
+// validate} são convertidos diretamente em MethodSpec, preservando caminhos de payload
+// com ":" ou ",". Os validadores dos objetos são acrescentados a -validate, exceto
+// quando a flag é informada na linha de comando.
+func applyMethods(fs *flag.FlagSet, value *yaml.Node, fromArgs map[string]bool) []error {
+	var methods []MethodSpec
+	var errs []error
+	for _, item := range value.Content {
+		switch item.Kind {
+		case yaml.ScalarNode:
+			spec, err := expandEnv(item.Value)
+			if err == nil {
+				var parsed []MethodSpec
+				parsed, err = ParseMethods(spec)
+				methods = append(methods, parsed...)
+			}
+			if err != nil {
+				errs = append(errs, err)
+			}
+		case yaml.MappingNode:
+			method, validators, err := fileMethod(item)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			methods = append(methods, method)
+			if fromArgs["validate"] {
+				continue // Validadores sobrescritos pela flag
+			}
+			for _, spec := range validators {
+				if err := setExpanded(fs, "validate", method.Method+":"+spec); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		default:
+			errs = append(errs, fmt.Errorf("listas aninhadas não são suportadas"))
+		}
+	}
+
+	*fs.Lookup("methods").Value.(methodsFlag).methods = methods
+	return errs
+}
+
+// Função fileMethod converte um objeto {method, weight, payload, validate} da lista de
+// métodos, com as variáveis de ambiente substituídas, e devolve seus validadores à parte.
+func fileMethod(item *yaml.Node) (MethodSpec, []string, error) {
+	var method struct {
+		Method   string   `yaml:"method"`
+		Weight   string   `yaml:"weight"`
+		Payload  string   `yaml:"payload"`
+		Validate []string `yaml:"validate"`
+	}
+	if err := decodeObject(item, &method); err != nil {
+		return MethodSpec{}, nil, err
+	}
+
+	var fields [3]string
+	for i, v := range []string{method.Method, method.Weight, method.Payload} {
+		expanded, err := expandEnv(v)
+		if err != nil {
+			return MethodSpec{}, nil, err
+		}
+		fields[i] = expanded
+	}
+	spec := MethodSpec{Method: fields[0], Weight: 1, PayloadFile: fields[2]}
+	if spec.Method == "" {
+		return MethodSpec{}, nil, fmt.Errorf("métodos da mistura devem ter method")
+	}
+	if fields[1] != "" {
+		weight, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return MethodSpec{}, nil, fmt.Errorf("peso inválido no método %q: %w", spec.Method, err)
+		}
+		spec.Weight = weight
+	}
+	return spec, method.Validate, nil
+}
+
+// Função decodeObject decodifica um objeto de uma lista, recusando campos desconhecidos.
+func decodeObject(item *yaml.Node, out interface{}) error {
+	known := make(map[string]bool)
+	t := reflect.TypeOf(out).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		known[t.Field(i).Tag.Get("yaml")] = true
+	}
+	for i := 0; i < len(item.Content); i += 2 {
+		if key := item.Content[i].Value; !known[key] {
+			return fmt.Errorf("campo desconhecido %q no objeto da linha %d", key, item.Line)
+		}
+	}
+	return item.Decode(out)
+}
+
+// Função expandEnv substitui as referências ${VAR} e ${VAR:-padrão} pelas variáveis de
+// ambiente; "$$" produz um "$" literal. Variáveis indefinidas sem padrão são um erro.
+func expandEnv(value string) (string, error) {
+	var b strings.Builder
+	var missing []string
+	for {
+		i := strings.IndexByte(value, '$')
+		if i < 0 || i == len(value)-1 {
+			b.WriteString(value)
+			break
+		}
+		b.WriteString(value[:i])
+		rest := value[i+1:]
+
+		switch {
+		case rest[0] == '$':
+			b.WriteByte('$')
+			value = rest[1:]
+		case rest[0] == '{':
+			end := strings.IndexByte(rest, '}')
+			if end < 0 {
+				return "", fmt.Errorf("referência %q sem \"}\"", value[i:])
+			}
+			name, def, hasDefault := strings.Cut(rest[1:end], ":-")
+			if v, ok := os.LookupEnv(name); ok && (v != "" || !hasDefault) {
+				b.WriteString(v)
+			} else if hasDefault {
+				b.WriteString(def)
+			} else {
+				missing = append(missing, name)
+			}
+			value = rest[end+1:]
+		default:
+			b.WriteByte('$')
+			value = rest
+		}
+	}
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("variável de ambiente indefinida: %s", strings.Join(missing, ", "))
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,147 @@
+package config
+
+// Importação de pacotes necessários.
+import (
+	"os"            // Pacote para gravar os arquivos de configuração do teste.
+	"path/filepath" // Pacote para montar os caminhos dos arquivos temporários.
+	"reflect"       // Pacote para comparar os valores carregados.
+	"testing"       // Pacote de testes.
+	"time"          // Pacote para manipulação de tempo e durações.
+)
+
+// writeConfigFile grava um arquivo de configuração temporário e devolve seu caminho.
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	t.Setenv("GORPCSTRESS_TEST_SERVER", "10.0.0.1:9000")
+	file := writeConfigFile(t, "cenario.yaml", `
+server: ${GORPCSTRESS_TEST_SERVER}
+method: ${GORPCSTRESS_TEST_METHOD:-Store.PlaceOrder}
+concurrency: 20
+requests: 500
+timeout: 2s
+seed: 0
+validate: ["field:Result=42"]
+threshold:
+  - p99 < 50ms
+methods:
+  - {method: Arithmetic.Multiply, weight: "${GORPCSTRESS_TEST_WEIGHT:-3}", validate: ["none"]}
+  - method: Store.PlaceOrder
+    payload: "pedidos/v2:lote,final.json"
+  - Users.Get:2
+`)
+
+	tests := []struct {
+		name  string
+		args  []string
+		check func(t *testing.T, cfg *Config)
+	}{
+		{"padrões", nil, func(t *testing.T, cfg *Config) {
+			if cfg.ServerAddress != "localhost:1234" || cfg.Concurrency != 50 || cfg.Timeout != 30*time.Second {
+				t.Errorf("padrões: servidor %s, concorrência %d, timeout %v", cfg.ServerAddress, cfg.Concurrency, cfg.Timeout)
+			}
+		}},
+		{"arquivo", []string{"-config", file}, func(t *testing.T, cfg *Config) {
+			if cfg.ServerAddress != "10.0.0.1:9000" || cfg.RPCMethod != "Store.PlaceOrder" || cfg.Concurrency != 20 || cfg.TotalRequests != 500 || cfg.Timeout != 2*time.Second {
+				t.Errorf("arquivo: servidor %s, método %s, concorrência %d, requisições %d, timeout %v", cfg.ServerAddress, cfg.RPCMethod, cfg.Concurrency, cfg.TotalRequests, cfg.Timeout)
+			}
+			want := []ValidatorSpec{{Spec: "field:Result=42"}, {Method: "Arithmetic.Multiply", Spec: "none"}}
+			if !reflect.DeepEqual(cfg.Validators, want) {
+				t.Errorf("validadores = %+v, esperado %+v", cfg.Validators, want)
+			}
+			// Os objetos viram MethodSpec sem passar pelo formato da flag, preservando ":" e ","
+			methods := []MethodSpec{{"Arithmetic.Multiply", 3, ""}, {"Store.PlaceOrder", 1, "pedidos/v2:lote,final.json"}, {"Users.Get", 2, ""}}
+			if !reflect.DeepEqual(cfg.MethodMix, methods) {
+				t.Errorf("métodos = %+v, esperado %+v", cfg.MethodMix, methods)
+			}
+			if len(cfg.Thresholds) != 1 || cfg.Seed != 0 {
+				t.Errorf("critérios %+v, semente %d", cfg.Thresholds, cfg.Seed)
+			}
+		}},
+		{"flags sobrescrevem o arquivo", []string{"-config", file, "-concurrency", "5", "-timeout", "0", "-seed", "7"}, func(t *testing.T, cfg *Config) {
+			if cfg.Concurrency != 5 || cfg.Timeout != 0 || cfg.Seed != 7 || cfg.TotalRequests != 500 {
+				t.Errorf("concorrência %d, timeout %v, semente %d, requisições %d", cfg.Concurrency, cfg.Timeout, cfg.Seed, cfg.TotalRequests)
+			}
+		}},
+		{"-validate sobrescreve os validadores do arquivo e dos métodos", []string{"-validate", "none", "-config", file}, func(t *testing.T, cfg *Config) {
+			if want := []ValidatorSpec{{Spec: "none"}}; !reflect.DeepEqual(cfg.Validators, want) {
+				t.Errorf("validadores = %+v, esperado %+v", cfg.Validators, want)
+			}
+		}},
+		{"-methods sobrescreve a mistura do arquivo", []string{"-config", file, "-methods", "Arithmetic.Multiply"}, func(t *testing.T, cfg *Config) {
+			if want := []MethodSpec{{Method: "Arithmetic.Multiply", Weight: 1}}; !reflect.DeepEqual(cfg.MethodMix, want) {
+				t.Errorf("métodos = %+v, esperado %+v", cfg.MethodMix, want)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadConfig(tt.args)
+			if err != nil {
+				t.Fatalf("LoadConfig(%q): %v", tt.args, err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestLoadConfigProblems(t *testing.T) {
+	file := writeConfigFile(t, "invalido.json", `{"concurrency": 0, "unknown": 1, "stages": "x"}`)
+	cfg, err := LoadConfig([]string{"-config", file, "-requests", "0"})
+	if cfg == nil {
+		t.Fatalf("LoadConfig deveria devolver a configuração com os problemas: %v", err)
+	}
+	problems, ok := err.(Problems)
+	if !ok || len(problems) != 4 {
+		t.Errorf("esperados 4 problemas (campo desconhecido, estágios, requisições, concorrência), obtido: %v", err)
+	}
+}
+
+func TestLoadConfigMethodProblems(t *testing.T) {
+	file := writeConfigFile(t, "metodos.yaml", `
+methods:
+  - {method: Users.Get, weight: muito}
+  - {metodo: Users.List}
+  - {weight: 2}
+  - [Users.Update]
+`)
+	if _, err := LoadConfig([]string{"-config", file}); err == nil || len(err.(Problems)) != 4 {
+		t.Errorf("esperados 4 problemas (peso, campo desconhecido, método ausente, lista aninhada), obtido: %v", err)
+	}
+}
+
+func TestLoadConfigSeed(t *testing.T) {
+	// Sem -seed cada execução sorteia uma semente própria
+	first, err := LoadConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := LoadConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Seed == second.Seed {
+		t.Errorf("duas execuções sem -seed sortearam a mesma semente %d", first.Seed)
+	}
+
+	zero, err := LoadConfig([]string{"-seed", "0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if zero.Seed != 0 {
+		t.Errorf("-seed 0 resultou na semente %d", zero.Seed)
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	if _, err := LoadConfig([]string{"-config", filepath.Join(t.TempDir(), "nao-existe.yaml")}); err == nil {
+		t.Error("arquivo inexistente deveria falhar")
+	}
+}
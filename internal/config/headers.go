@@ -0,0 +1,36 @@
+package config
+
+// Importação de pacotes necessários.
+import (
+	"fmt"      // Pacote para formatação de strings e mensagens de erro.
+	"net/http" // Pacote com o tipo dos cabeçalhos HTTP.
+	"strings"  // Pacote para manipulação de strings.
+)
+
+// Tipo headersFlag permite informar cabeçalhos HTTP pela linha de comando no formato
+// "Nome: valor". A flag pode ser repetida.
+type headersFlag struct {
+	headers http.Header
+}
+
+// Método String devolve a representação textual dos cabeçalhos configurados.
+func (f headersFlag) String() string {
+	var parts []string
+	for name, values := range f.headers {
+		for _, value := range values {
+			parts = append(parts, name+": "+value)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Método Set acrescenta o cabeçalho informado na linha de comando.
+func (f headersFlag) Set(value string) error {
+	name, v, found := strings.Cut(value, ":")
+	name = strings.TrimSpace(name)
+	if !found || name == "" {
+		return fmt.Errorf("cabeçalho %q deve estar no formato \"Nome: valor\"", value)
+	}
+	f.headers.Add(name, strings.TrimSpace(v))
+	return nil
+}
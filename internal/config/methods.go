@@ -0,0 +1,113 @@
+package config
+
+// Importação de pacotes necessários.
+import (
+	"fmt"     // Pacote para formatação de strings e mensagens de erro.
+	"strconv" // Pacote para conversão de números.
+	"strings" // Pacote para manipulação de strings.
+)
+
+// Estrutura MethodSpec descreve um método da mistura de tráfego. A cada requisição o
+// método é sorteado com probabilidade proporcional ao seu peso.
+type MethodSpec struct {
+	Method      string // Método RPC (ex: "Users.Get").
+	Weight      int    // Peso relativo do método na mistura.
+	PayloadFile string // Arquivo de payload do método (padrão: -payload ou o payload padrão).
+}
+
+// Método Methods retorna a mistura de métodos do teste. Sem -methods, a mistura tem
+// apenas o método de -method, com o payload de -payload.
+func (c *Config) Methods() []MethodSpec {
+	if len(c.MethodMix) > 0 {
+		return c.MethodMix
+	}
+	return []MethodSpec{{Method: c.RPCMethod, Weight: 1, PayloadFile: c.PayloadFile}}
+}
+
+// Método MethodNames lista os métodos testados, separados por vírgulas.
+func (c *Config) MethodNames() string {
+	methods := c.Methods()
+	names := make([]string, len(methods))
+	for i, m := range methods {
+		names[i] = m.Method
+	}
+	return strings.Join(names, ", ")
+}
+
+// Tipo methodsFlag permite definir a mistura de métodos pela linha de comando no
+// formato "Método:peso[:payload],..." (ex: "Users.Get:70:get.json,Users.List:10").
+type methodsFlag struct {
+	methods *[]MethodSpec
+}
+
+// Método String devolve a representação textual da mistura configurada.
+func (f methodsFlag) String() string {
+	if f.methods == nil {
+		return ""
+	}
+	parts := make([]string, 0, len(*f.methods))
+	for _, m := range *f.methods {
+		part := fmt.Sprintf("%s:%d", m.Method, m.Weight)
+		if m.PayloadFile != "" {
+			part += ":" + m.PayloadFile
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Método Set interpreta a mistura de métodos informada na linha de comando.
+func (f methodsFlag) Set(value string) error {
+	methods, err := ParseMethods(value)
+	if err != nil {
+		return err
+	}
+	*f.methods = methods
+	return nil
+}
+
+// Função ParseMethods interpreta uma mistura de métodos no formato "Método:peso[:payload],...".
+// Sem o peso, o método recebe peso 1.
+func ParseMethods(spec string) ([]MethodSpec, error) {
+	var methods []MethodSpec
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fields := strings.SplitN(part, ":", 3)
+		m := MethodSpec{Method: strings.TrimSpace(fields[0]), Weight: 1}
+		if len(fields) > 1 {
+			weight, err := strconv.Atoi(strings.TrimSpace(fields[1]))
+			if err != nil {
+				return nil, fmt.Errorf("peso inválido no método %q: %w", part, err)
+			}
+			m.Weight = weight
+		}
+		if len(fields) > 2 {
+			m.PayloadFile = strings.TrimSpace(fields[2])
+		}
+		methods = append(methods, m)
+	}
+	return methods, nil
+}
+
+// Função validateMethods verifica a mistura de métodos: nomes preenchidos e únicos,
+// já que as métricas são separadas por método, e pesos positivos.
+func validateMethods(methods []MethodSpec) error {
+	seen := make(map[string]bool, len(methods))
+	for i, m := range methods {
+		if m.Method == "" {
+			return fmt.Errorf("método %d da mistura: nome não pode ser vazio", i+1)
+		}
+		if seen[m.Method] {
+			return fmt.Errorf("método %q repetido na mistura", m.Method)
+		}
+		seen[m.Method] = true
+		if m.Weight < 1 {
+			return fmt.Errorf("método %q: peso deve ser maior que zero", m.Method)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package config
+
+// Importação de pacotes necessários.
+import (
+	"reflect" // Pacote para comparar as misturas interpretadas.
+	"testing" // Pacote de testes.
+)
+
+func TestParseMethods(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []MethodSpec
+		wantErr bool
+	}{
+		{"Users.Get:70:get.json, Users.List:10,", []MethodSpec{{"Users.Get", 70, "get.json"}, {"Users.List", 10, ""}}, false},
+		{"Users.Get", []MethodSpec{{"Users.Get", 1, ""}}, false},
+		{"Users.Get:muito", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMethods(tt.spec)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseMethods(%q) = %v, %v; esperado %v, erro %v", tt.spec, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateMethods(t *testing.T) {
+	tests := []struct {
+		name    string
+		methods []MethodSpec
+		wantErr bool
+	}{
+		{"mistura", []MethodSpec{{"Users.Get", 70, ""}, {"Users.List", 30, ""}}, false},
+		{"sem nome", []MethodSpec{{"", 1, ""}}, true},
+		{"repetido", []MethodSpec{{"Users.Get", 1, ""}, {"Users.Get", 2, ""}}, true},
+		{"peso zero", []MethodSpec{{"Users.Get", 0, ""}}, true},
+	}
+	for _, tt := range tests {
+		if err := validateMethods(tt.methods); (err != nil) != tt.wantErr {
+			t.Errorf("%s: validateMethods = %v, esperado erro %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMethodsDefault(t *testing.T) {
+	cfg := &Config{RPCMethod: "Arithmetic.Multiply", PayloadFile: "p.json"}
+	if want := []MethodSpec{{"Arithmetic.Multiply", 1, "p.json"}}; !reflect.DeepEqual(cfg.Methods(), want) {
+		t.Errorf("Methods() = %v, esperado %v", cfg.Methods(), want)
+	}
+}
@@ -0,0 +1,123 @@
+package config
+
+// Importação de pacotes necessários.
+import (
+	"flag"    // Pacote para manipulação de flags de linha de comando.
+	"fmt"     // Pacote para formatação de strings e mensagens de erro.
+	"strconv" // Pacote para conversão de números.
+	"strings" // Pacote para manipulação de strings.
+)
+
+// DefaultRegression define os limites de regressão usados quando nenhum é informado.
+const DefaultRegression = "p99=10%,error_rate=0.5%"
+
+// Métricas aceitas nos limites de regressão. As latências se referem ao tempo de
+// resposta; "rps" limita a queda de throughput e "error_rate" o aumento da taxa de erro.
+var RegressionMetrics = []string{"rps", "error_rate", "mean", "p50", "p90", "p99", "p999", "max"}
+
+// Estrutura RegressionThreshold define a piora máxima tolerada em uma métrica ao
+// comparar uma execução com a linha de base. Para latências e "rps" o limite é uma
+// variação relativa em porcentagem; para "error_rate" é um aumento em pontos percentuais.
+type RegressionThreshold struct {
+	Metric string  // Métrica comparada (ex: "p99").
+	Limit  float64 // Piora máxima tolerada, em porcentagem ou pontos percentuais.
+}
+
+// Função ParseRegressionThresholds interpreta limites no formato "métrica=valor%,..."
+// (ex: "p99=10%,rps=5%,error_rate=0.5%"). O sinal de porcentagem é opcional e, para
+// "error_rate", o valor é o aumento máximo em pontos percentuais.
+func ParseRegressionThresholds(spec string) ([]RegressionThreshold, error) {
+	var thresholds []RegressionThreshold
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		metric, value, found := strings.Cut(part, "=")
+		if !found {
+			return nil, fmt.Errorf("limite de regressão %q deve estar no formato métrica=valor%%", part)
+		}
+
+		metric = strings.TrimSpace(metric)
+		if !isRegressionMetric(metric) {
+			return nil, fmt.Errorf("métrica de regressão desconhecida %q (aceitas: %s)", metric, strings.Join(RegressionMetrics, ", "))
+		}
+
+		limit, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("valor inválido no limite de regressão %q", part)
+		}
+
+		thresholds = append(thresholds, RegressionThreshold{Metric: metric, Limit: limit})
+	}
+	return thresholds, nil
+}
+
+// Função isRegressionMetric verifica se a métrica é aceita nos limites de regressão.
+func isRegressionMetric(metric string) bool {
+	for _, m := range RegressionMetrics {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// Tipo regressionFlag permite definir os limites de regressão pela linha de comando.
+type regressionFlag struct {
+	thresholds *[]RegressionThreshold
+}
+
+// Método String devolve a representação textual dos limites configurados.
+func (f regressionFlag) String() string {
+	if f.thresholds == nil {
+		return ""
+	}
+	parts := make([]string, 0, len(*f.thresholds))
+	for _, t := range *f.thresholds {
+		parts = append(parts, fmt.Sprintf("%s=%g%%", t.Metric, t.Limit))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Método Set interpreta os limites de regressão informados na linha de comando.
+func (f regressionFlag) Set(value string) error {
+	thresholds, err := ParseRegressionThresholds(value)
+	if err != nil {
+		return err
+	}
+	*f.thresholds = thresholds
+	return nil
+}
+
+// Estrutura CompareConfig armazena as configurações do subcomando compare.
+type CompareConfig struct {
+	BaselineFile string                // Relatório JSON da linha de base.
+	CurrentFile  string                // Relatório JSON da execução a comparar.
+	Regression   []RegressionThreshold // Limites de regressão.
+}
+
+// Função LoadCompareConfig carrega as configurações do subcomando compare a partir
+// dos argumentos "[flags] base.json atual.json".
+func LoadCompareConfig(args []string) (*CompareConfig, error) {
+	cfg := &CompareConfig{}
+	cfg.Regression, _ = ParseRegressionThresholds(DefaultRegression)
+
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Uso: gorpcstress compare [flags] base.json atual.json")
+		fs.PrintDefaults()
+	}
+	fs.Var(regressionFlag{&cfg.Regression}, "regression", "Limites de regressão, relativos em % e em pontos percentuais para error_rate (ex: p99=10%,rps=5%,error_rate=0.5%)")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return nil, fmt.Errorf("compare exige dois relatórios JSON")
+	}
+	cfg.BaselineFile, cfg.CurrentFile = fs.Arg(0), fs.Arg(1)
+	return cfg, nil
+}
@@ -0,0 +1,29 @@
+package config
+
+// Importação de pacotes necessários.
+import (
+	"reflect" // Pacote para comparar os limites interpretados.
+	"testing" // Pacote de testes.
+)
+
+func TestParseRegressionThresholds(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []RegressionThreshold
+		wantErr bool
+	}{
+		{DefaultRegression, []RegressionThreshold{{"p99", 10}, {"error_rate", 0.5}}, false},
+		{" rps=5 , p999=2.5%,", []RegressionThreshold{{"rps", 5}, {"p999", 2.5}}, false},
+		{"", nil, false},
+		{"p99", nil, true},
+		{"p95=10%", nil, true},
+		{"p99=dez%", nil, true},
+		{"p99=-1%", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRegressionThresholds(tt.spec)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseRegressionThresholds(%q) = %v, %v; esperado %v, erro %v", tt.spec, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
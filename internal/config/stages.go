@@ -0,0 +1,113 @@
+package config
+
+// Importação de pacotes necessários.
+import (
+	"fmt"     // Pacote para formatação de strings e mensagens de erro.
+	"strconv" // Pacote para conversão de números.
+	"strings" // Pacote para manipulação de strings.
+	"time"    // Pacote para manipulação de tempo e durações.
+)
+
+// Alvos possíveis para os estágios do perfil de carga.
+const (
+	StageTargetRate        = "rate"        // O alvo de cada estágio é uma taxa em req/s.
+	StageTargetConcurrency = "concurrency" // O alvo de cada estágio é um número de workers.
+)
+
+// Estrutura Stage descreve um estágio do perfil de carga. O alvo varia linearmente,
+// ao longo de Duration, do alvo do estágio anterior (zero no primeiro) até Target.
+type Stage struct {
+	Duration time.Duration // Duração do estágio.
+	Target   float64       // Alvo (req/s ou workers) ao final do estágio.
+}
+
+// Método Label descreve o estágio para relatórios, a partir do alvo anterior.
+func (s Stage) Label(from float64) string {
+	if s.Duration == 0 {
+		return fmt.Sprintf("salto %g→%g", from, s.Target)
+	}
+	if from == s.Target {
+		return fmt.Sprintf("platô %g por %v", s.Target, s.Duration)
+	}
+	return fmt.Sprintf("rampa %g→%g em %v", from, s.Target, s.Duration)
+}
+
+// Tipo stagesFlag permite definir os estágios pela linha de comando no formato
+// "duração:alvo,duração:alvo,..." (ex: "30s:500,2m:500,10s:2000,30s:0").
+type stagesFlag struct {
+	stages *[]Stage
+}
+
+// Método String devolve a representação textual dos estágios configurados.
+func (f stagesFlag) String() string {
+	if f.stages == nil {
+		return ""
+	}
+	parts := make([]string, 0, len(*f.stages))
+	for _, s := range *f.stages {
+		parts = append(parts, fmt.Sprintf("%v:%g", s.Duration, s.Target))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Método Set interpreta a lista de estágios informada na linha de comando.
+func (f stagesFlag) Set(value string) error {
+	stages, err := ParseStages(value)
+	if err != nil {
+		return err
+	}
+	*f.stages = stages
+	return nil
+}
+
+// Função ParseStages interpreta uma lista de estágios no formato "duração:alvo,...".
+func ParseStages(spec string) ([]Stage, error) {
+	var stages []Stage
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		durationText, targetText, found := strings.Cut(part, ":")
+		if !found {
+			return nil, fmt.Errorf("estágio %q deve estar no formato duração:alvo", part)
+		}
+
+		duration, err := time.ParseDuration(durationText)
+		if err != nil {
+			return nil, fmt.Errorf("duração inválida no estágio %q: %w", part, err)
+		}
+
+		target, err := strconv.ParseFloat(targetText, 64)
+		if err != nil {
+			return nil, fmt.Errorf("alvo inválido no estágio %q: %w", part, err)
+		}
+
+		stages = append(stages, Stage{Duration: duration, Target: target})
+	}
+	return stages, nil
+}
+
+// Função validateStages verifica se o perfil de carga é executável.
+func validateStages(stages []Stage, target string) error {
+	if target != StageTargetRate && target != StageTargetConcurrency {
+		return fmt.Errorf("alvo dos estágios deve ser %q ou %q", StageTargetRate, StageTargetConcurrency)
+	}
+
+	var total time.Duration
+	for i, s := range stages {
+		if s.Duration < 0 {
+			return fmt.Errorf("estágio %d: duração não pode ser negativa", i+1)
+		}
+		if s.Target < 0 {
+			return fmt.Errorf("estágio %d: alvo não pode ser negativo", i+1)
+		}
+		total += s.Duration
+	}
+
+	if total <= 0 {
+		return fmt.Errorf("o perfil de estágios deve ter duração total maior que zero")
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package config
+
+// Importação de pacotes necessários.
+import (
+	"reflect" // Pacote para comparar os estágios interpretados.
+	"testing" // Pacote de testes.
+	"time"    // Pacote para manipulação de tempo e durações.
+)
+
+func TestParseStages(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []Stage
+		wantErr bool
+	}{
+		{"30s:500, 2m:500,10s:2000,30s:0", []Stage{{30 * time.Second, 500}, {2 * time.Minute, 500}, {10 * time.Second, 2000}, {30 * time.Second, 0}}, false},
+		{"0s:100,1m:100", []Stage{{0, 100}, {time.Minute, 100}}, false},
+		{"1.5s:0.5,", []Stage{{1500 * time.Millisecond, 0.5}}, false},
+		{"30s", nil, true},
+		{"trinta:100", nil, true},
+		{"30s:muito", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseStages(tt.spec)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseStages(%q) = %v, %v; esperado %v, erro %v", tt.spec, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateStages(t *testing.T) {
+	tests := []struct {
+		name    string
+		stages  []Stage
+		target  string
+		wantErr bool
+	}{
+		{"rampa", []Stage{{time.Minute, 100}, {time.Minute, 0}}, StageTargetRate, false},
+		{"salto seguido de platô", []Stage{{0, 100}, {time.Minute, 100}}, StageTargetConcurrency, false},
+		{"alvo desconhecido", []Stage{{time.Minute, 100}}, "workers", true},
+		{"duração negativa", []Stage{{-time.Second, 100}, {time.Minute, 100}}, StageTargetRate, true},
+		{"alvo negativo", []Stage{{time.Minute, -1}}, StageTargetRate, true},
+		{"sem duração", []Stage{{0, 100}}, StageTargetRate, true},
+	}
+	for _, tt := range tests {
+		if err := validateStages(tt.stages, tt.target); (err != nil) != tt.wantErr {
+			t.Errorf("%s: validateStages = %v, esperado erro %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
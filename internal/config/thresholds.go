@@ -0,0 +1,133 @@
+package config
+
+// Importação de pacotes necessários.
+import (
+	"fmt"     // Pacote para formatação de strings e mensagens de erro.
+	"strconv" // Pacote para conversão de números.
+	"strings" // Pacote para manipulação de strings.
+	"time"    // Pacote para manipulação de tempo e durações.
+)
+
+// Métricas aceitas nos critérios de aprovação. As latências se referem ao tempo de
+// resposta e são comparadas como durações (ex: "50ms"); "error_rate" é uma porcentagem,
+// "rps" uma taxa em req/s e "requests"/"errors"/"invalid" são contagens.
+var ThresholdMetrics = []string{"rps", "error_rate", "requests", "errors", "invalid", "min", "mean", "p50", "p90", "p99", "p999", "max"}
+
+// Operadores aceitos nos critérios de aprovação, em ordem de reconhecimento (os de
+// dois caracteres antes dos de um).
+var thresholdOperators = []string{"<=", ">=", "<", ">"}
+
+// Estrutura Threshold define um critério absoluto de aprovação do teste, como
+// "p99 < 50ms" ou "rps >= 1000".
+type Threshold struct {
+	Expr   string  // Expressão original, usada na exibição.
+	Metric string  // Métrica avaliada (ex: "p99").
+	Op     string  // Operador de comparação: "<", "<=", ">" ou ">=".
+	Value  float64 // Valor limite; latências em nanossegundos e taxas de erro em porcentagem.
+}
+
+// Método IsLatency indica se o critério se refere a uma latência.
+func (t Threshold) IsLatency() bool {
+	switch t.Metric {
+	case "rps", "error_rate", "requests", "errors", "invalid":
+		return false
+	}
+	return true
+}
+
+// Método Holds verifica se o valor medido satisfaz o critério.
+func (t Threshold) Holds(actual float64) bool {
+	switch t.Op {
+	case "<":
+		return actual < t.Value
+	case "<=":
+		return actual <= t.Value
+	case ">":
+		return actual > t.Value
+	default:
+		return actual >= t.Value
+	}
+}
+
+// Função ParseThreshold interpreta uma expressão no formato "métrica operador valor"
+// (ex: "p99 < 50ms", "error_rate < 0.1%", "rps >= 1000").
+func ParseThreshold(expr string) (Threshold, error) {
+	expr = strings.TrimSpace(expr)
+
+	t := Threshold{Expr: expr}
+	var value string
+	for _, op := range thresholdOperators {
+		if metric, rest, found := strings.Cut(expr, op); found {
+			t.Metric, t.Op, value = strings.TrimSpace(metric), op, strings.TrimSpace(rest)
+			break
+		}
+	}
+	if t.Op == "" {
+		return Threshold{}, fmt.Errorf("critério %q deve estar no formato métrica operador valor (ex: p99 < 50ms)", expr)
+	}
+	if !isThresholdMetric(t.Metric) {
+		return Threshold{}, fmt.Errorf("métrica desconhecida no critério %q (aceitas: %s)", expr, strings.Join(ThresholdMetrics, ", "))
+	}
+
+	if t.IsLatency() {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return Threshold{}, fmt.Errorf("latência inválida no critério %q (ex: 50ms)", expr)
+		}
+		t.Value = float64(d)
+		return t, nil
+	}
+
+	if t.Metric == "error_rate" {
+		value = strings.TrimSuffix(value, "%")
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || v < 0 {
+		return Threshold{}, fmt.Errorf("valor inválido no critério %q", expr)
+	}
+	t.Value = v
+	return t, nil
+}
+
+// Função isThresholdMetric verifica se a métrica é aceita nos critérios de aprovação.
+func isThresholdMetric(metric string) bool {
+	for _, m := range ThresholdMetrics {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// Tipo thresholdsFlag permite informar critérios de aprovação pela linha de comando.
+// A flag pode ser repetida e cada ocorrência aceita vários critérios separados por vírgula.
+type thresholdsFlag struct {
+	thresholds *[]Threshold
+}
+
+// Método String devolve a representação textual dos critérios configurados.
+func (f thresholdsFlag) String() string {
+	if f.thresholds == nil {
+		return ""
+	}
+	exprs := make([]string, 0, len(*f.thresholds))
+	for _, t := range *f.thresholds {
+		exprs = append(exprs, t.Expr)
+	}
+	return strings.Join(exprs, ",")
+}
+
+// Método Set acrescenta os critérios informados na linha de comando.
+func (f thresholdsFlag) Set(value string) error {
+	for _, expr := range strings.Split(value, ",") {
+		if strings.TrimSpace(expr) == "" {
+			continue
+		}
+		t, err := ParseThreshold(expr)
+		if err != nil {
+			return err
+		}
+		*f.thresholds = append(*f.thresholds, t)
+	}
+	return nil
+}
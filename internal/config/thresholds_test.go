@@ -0,0 +1,55 @@
+package config
+
+// Importação de pacotes necessários.
+import (
+	"testing" // Pacote de testes.
+	"time"    // Pacote para manipulação de tempo e durações.
+)
+
+func TestParseThreshold(t *testing.T) {
+	tests := []struct {
+		expr    string
+		metric  string
+		op      string
+		value   float64
+		wantErr bool
+	}{
+		{"p99 < 50ms", "p99", "<", float64(50 * time.Millisecond), false},
+		{"error_rate<=0.1%", "error_rate", "<=", 0.1, false},
+		{" rps >= 1000 ", "rps", ">=", 1000, false},
+		{"errors > 0", "errors", ">", 0, false},
+		{"p99 = 50ms", "", "", 0, true},
+		{"p95 < 50ms", "", "", 0, true},
+		{"p99 < 50", "", "", 0, true},
+		{"rps >= muito", "", "", 0, true},
+		{"errors < -1", "", "", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseThreshold(tt.expr)
+		if (err != nil) != tt.wantErr || got.Metric != tt.metric || got.Op != tt.op || got.Value != tt.value {
+			t.Errorf("ParseThreshold(%q) = %+v, %v; esperado %s %s %v, erro %v", tt.expr, got, err, tt.metric, tt.op, tt.value, tt.wantErr)
+		}
+	}
+}
+
+func TestThresholdHolds(t *testing.T) {
+	tests := []struct {
+		op     string
+		actual float64
+		want   bool
+	}{
+		{"<", 9, true},
+		{"<", 10, false},
+		{"<=", 10, true},
+		{">", 10, false},
+		{">", 11, true},
+		{">=", 10, true},
+		{">=", 9, false},
+	}
+	for _, tt := range tests {
+		th := Threshold{Op: tt.op, Value: 10}
+		if got := th.Holds(tt.actual); got != tt.want {
+			t.Errorf("%v %s 10 = %v, esperado %v", tt.actual, tt.op, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package config
+
+// Importação de pacotes necessários.
+import (
+	"crypto/tls"  // Pacote com a configuração das conexões TLS.
+	"crypto/x509" // Pacote para o conjunto de autoridades certificadoras.
+	"fmt"         // Pacote para formatação de strings e mensagens de erro.
+	"os"          // Pacote para leitura dos arquivos de certificados.
+	"sort"        // Pacote para ordenação das versões aceitas.
+	"strings"     // Pacote para manipulação de strings.
+
+	// Dependência interna do projeto.
+	"github.com/denner-s/gorpcstress/pkg/rpcclient" // Redes aceitas nos endereços.
+)
+
+// Versões mínimas de TLS aceitas em -tls-min-version.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Método UsesTLS indica se as conexões com o servidor usam TLS: com -tls no transporte
+// TCP ou com uma URL https:// no transporte HTTP.
+func (c *Config) UsesTLS() bool {
+	return c.TLS || strings.HasPrefix(c.ServerAddress, "https://")
+}
+
+// Método validateTLS verifica a consistência das opções de TLS.
+func (c *Config) validateTLS() error {
+	customized := c.TLSCAFile != "" || c.TLSCertFile != "" || c.TLSKeyFile != "" || c.TLSServerName != "" || c.TLSInsecure
+	if customized && !c.UsesTLS() {
+		return fmt.Errorf("opções -tls-* exigem -tls ou um servidor https://")
+	}
+	if c.TLS && strings.HasPrefix(c.ServerAddress, "http://") {
+		return fmt.Errorf("-tls não se aplica a URLs http://; use https://")
+	}
+	// Sockets Unix não têm um host a verificar no certificado do servidor.
+	if c.TLS && strings.HasPrefix(c.ServerAddress, rpcclient.NetworkUnix+"://") && c.TLSServerName == "" && !c.TLSInsecure {
+		return fmt.Errorf("-tls com socket Unix exige -tls-server-name ou -tls-insecure")
+	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("-tls-cert e -tls-key devem ser informados juntos")
+	}
+	if _, ok := tlsVersions[c.TLSMinVersion]; !ok {
+		versions := make([]string, 0, len(tlsVersions))
+		for v := range tlsVersions {
+			versions = append(versions, v)
+		}
+		sort.Strings(versions)
+		return fmt.Errorf("versão mínima de TLS deve ser uma de: %s", strings.Join(versions, ", "))
+	}
+	return nil
+}
+
+// Método TLSConfig monta a configuração TLS das conexões a partir das opções -tls-*,
+// carregando a CA e o certificado do cliente (mTLS). Retorna nil quando o teste não
+// usa TLS.
+func (c *Config) TLSConfig() (*tls.Config, error) {
+	if !c.UsesTLS() {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         c.TLSServerName,
+		InsecureSkipVerify: c.TLSInsecure,
+		MinVersion:         tlsVersions[c.TLSMinVersion],
+	}
+
+	// A CA informada substitui as autoridades do sistema na verificação do servidor.
+	if c.TLSCAFile != "" {
+		pem, err := os.ReadFile(c.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler a CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("nenhum certificado PEM válido em %s", c.TLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	// Certificado do cliente para servidores que exigem TLS mútuo.
+	if c.TLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao carregar o certificado do cliente: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,64 @@
+package config
+
+// Importação de pacotes necessários.
+import (
+	"os"            // Pacote para criar os arquivos de certificados do teste.
+	"path/filepath" // Pacote para montar os caminhos dos arquivos temporários.
+	"strings"       // Pacote para inspecionar as mensagens de erro.
+	"testing"       // Pacote de testes.
+)
+
+func TestValidateTLS(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		message string // Trecho esperado do erro; vazio quando a configuração é válida.
+	}{
+		{"sem TLS", Config{ServerAddress: "localhost:1234", TLSMinVersion: "1.2"}, ""},
+		{"TLS no TCP", Config{ServerAddress: "localhost:1234", TLS: true, TLSCAFile: "ca.pem", TLSMinVersion: "1.3"}, ""},
+		{"URL https", Config{ServerAddress: "https://localhost/rpc", TLSInsecure: true, TLSMinVersion: "1.2"}, ""},
+		{"opções sem TLS", Config{ServerAddress: "localhost:1234", TLSCAFile: "ca.pem", TLSMinVersion: "1.2"}, "exigem -tls"},
+		{"TLS em URL http", Config{ServerAddress: "http://localhost/rpc", TLS: true, TLSMinVersion: "1.2"}, "https://"},
+		{"certificado sem chave", Config{ServerAddress: "localhost:1234", TLS: true, TLSCertFile: "cert.pem", TLSMinVersion: "1.2"}, "juntos"},
+		{"versão desconhecida", Config{ServerAddress: "localhost:1234", TLS: true, TLSMinVersion: "2.0"}, "1.0, 1.1, 1.2, 1.3"},
+	}
+	for _, tt := range tests {
+		err := tt.cfg.validateTLS()
+		if (err == nil) != (tt.message == "") || (err != nil && !strings.Contains(err.Error(), tt.message)) {
+			t.Errorf("%s: erro %v, esperado %q", tt.name, err, tt.message)
+		}
+	}
+}
+
+func TestTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalido.pem")
+	if err := os.WriteFile(invalid, []byte("não é PEM"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if tlsConfig, err := (&Config{ServerAddress: "localhost:1234"}).TLSConfig(); tlsConfig != nil || err != nil {
+		t.Errorf("teste sem TLS retornou %v, %v", tlsConfig, err)
+	}
+
+	cfg := &Config{ServerAddress: "localhost:1234", TLS: true, TLSServerName: "rpc.local", TLSMinVersion: "1.3"}
+	tlsConfig, err := cfg.TLSConfig()
+	if err != nil || tlsConfig.ServerName != "rpc.local" || tlsConfig.MinVersion != tlsVersions["1.3"] {
+		t.Errorf("configuração TLS %+v, erro %v", tlsConfig, err)
+	}
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		message string
+	}{
+		{"CA ausente", Config{TLS: true, TLSCAFile: filepath.Join(dir, "ausente.pem")}, "falha ao ler a CA"},
+		{"CA inválida", Config{TLS: true, TLSCAFile: invalid}, "nenhum certificado PEM"},
+		{"certificado inválido", Config{TLS: true, TLSCertFile: invalid, TLSKeyFile: invalid}, "certificado do cliente"},
+	}
+	for _, tt := range tests {
+		if _, err := tt.cfg.TLSConfig(); err == nil || !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("%s: erro %v, esperado %q", tt.name, err, tt.message)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package config
+
+// Importação de pacotes necessários.
+import (
+	"fmt"     // Pacote para formatação de strings e mensagens de erro.
+	"strings" // Pacote para manipulação de strings.
+)
+
+// Estrutura ValidatorSpec associa uma especificação de validador (ex: "range:Total=0..100")
+// a um método RPC. Method vazio aplica o validador a qualquer método.
+type ValidatorSpec struct {
+	Method string // Método RPC validado (opcional).
+	Spec   string // Especificação do validador, interpretada pelo pacote validate.
+}
+
+// Função ParseValidatorSpec interpreta "[Método:]especificação". O prefixo é reconhecido
+// como método quando contém um ponto (ex: "Store.PlaceOrder:field:Customer=ana").
+func ParseValidatorSpec(value string) (ValidatorSpec, error) {
+	value = strings.TrimSpace(value)
+	if prefix, rest, found := strings.Cut(value, ":"); found && strings.Contains(prefix, ".") {
+		return ValidatorSpec{Method: prefix, Spec: strings.TrimSpace(rest)}, nil
+	}
+	if value == "" {
+		return ValidatorSpec{}, fmt.Errorf("especificação de validador vazia")
+	}
+	return ValidatorSpec{Spec: value}, nil
+}
+
+// Método ValidatorsFor retorna as especificações aplicáveis ao método informado.
+func (c *Config) ValidatorsFor(method string) []string {
+	var specs []string
+	for _, v := range c.Validators {
+		if v.Method == "" || v.Method == method {
+			specs = append(specs, v.Spec)
+		}
+	}
+	return specs
+}
+
+// Tipo validatorsFlag permite informar validadores pela linha de comando. A flag pode
+// ser repetida; todos os validadores aplicáveis ao método precisam aceitar a resposta.
+type validatorsFlag struct {
+	validators *[]ValidatorSpec
+}
+
+// Método String devolve a representação textual dos validadores configurados.
+func (f validatorsFlag) String() string {
+	if f.validators == nil {
+		return ""
+	}
+	specs := make([]string, 0, len(*f.validators))
+	for _, v := range *f.validators {
+		if v.Method != "" {
+			specs = append(specs, v.Method+":"+v.Spec)
+		} else {
+			specs = append(specs, v.Spec)
+		}
+	}
+	return strings.Join(specs, " ")
+}
+
+// Método Set acrescenta o validador informado na linha de comando.
+func (f validatorsFlag) Set(value string) error {
+	spec, err := ParseValidatorSpec(value)
+	if err != nil {
+		return err
+	}
+	*f.validators = append(*f.validators, spec)
+	return nil
+}
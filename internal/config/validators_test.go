@@ -0,0 +1,48 @@
+package config
+
+// Importação de pacotes necessários.
+import (
+	"reflect" // Pacote para comparar os validadores interpretados.
+	"testing" // Pacote de testes.
+)
+
+func TestParseValidatorSpec(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    ValidatorSpec
+		wantErr bool
+	}{
+		{"field:Result=15", ValidatorSpec{Spec: "field:Result=15"}, false},
+		{" Store.PlaceOrder: range:Total=0..100 ", ValidatorSpec{Method: "Store.PlaceOrder", Spec: "range:Total=0..100"}, false},
+		{"regex:items.0.sku=^A", ValidatorSpec{Spec: "regex:items.0.sku=^A"}, false},
+		{"none", ValidatorSpec{Spec: "none"}, false},
+		{" ", ValidatorSpec{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseValidatorSpec(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseValidatorSpec(%q) = %+v, %v; esperado %+v, erro %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestValidatorsFor(t *testing.T) {
+	cfg := &Config{Validators: []ValidatorSpec{
+		{Spec: "contains:status=ok"},
+		{Method: "Store.PlaceOrder", Spec: "range:Total=0..100"},
+		{Method: "Arithmetic.Multiply", Spec: "field:Result=15"},
+	}}
+	tests := []struct {
+		method string
+		want   []string
+	}{
+		{"Store.PlaceOrder", []string{"contains:status=ok", "range:Total=0..100"}},
+		{"Arithmetic.Multiply", []string{"contains:status=ok", "field:Result=15"}},
+		{"Store.Cancel", []string{"contains:status=ok"}},
+	}
+	for _, tt := range tests {
+		if got := cfg.ValidatorsFor(tt.method); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ValidatorsFor(%q) = %v, esperado %v", tt.method, got, tt.want)
+		}
+	}
+}
@@ -2,47 +2,327 @@ package metrics
 
 // Importação de pacotes necessários.
 import (
-	"sort" // Pacote para ordenação de slices.
 	"time" // Pacote para manipulação de tempo e durações.
 )
 
 // Estrutura Result armazena o resultado de uma requisição individual.
+// Duration é o tempo de serviço, medido a partir do envio efetivo da chamada, enquanto
+// ResponseTime é medido a partir do horário em que o envio foi agendado pelo runner e,
+// portanto, inclui o tempo de espera na fila (correção de omissão coordenada).
 type Result struct {
-	Duration time.Duration // Duração da requisição.
-	Error    error         // Erro (se houver) durante a requisição.
+	Duration     time.Duration // Tempo de serviço da requisição.
+	ResponseTime time.Duration // Tempo de resposta a partir do envio agendado.
+	Error        error         // Erro (se houver) durante a requisição.
+	Category     ErrorCategory // Categoria de Error (vazia sem erro).
+	Invalid      bool          // Indica que Error é uma falha de validação da resposta, e não de transporte.
+	Late         bool          // Indica que o envio ocorreu depois do horário agendado (modo -rate).
+	Dropped      bool          // Indica um envio agendado descartado por falta de worker livre (modo -rate).
+	Abandoned    bool          // Indica uma chamada em voo abandonada ao fim do prazo de interrupção.
+	TimedOut     bool          // Indica que Error é um timeout e a chamada foi abandonada ainda em voo no servidor.
+	Retries      int           // Novas tentativas feitas após falhas de conexão (-retries).
+	GaveUp       bool          // Indica que Error é a falha da última tentativa, esgotadas as novas tentativas.
+	Stage        int           // Índice do estágio do perfil de carga em que a requisição foi agendada.
+	Method       int           // Índice do método da mistura sorteado para a requisição.
+	Conn         *ConnResult   // Quando presente, o resultado descreve uma conexão, e não uma requisição.
+}
+
+// Estrutura ConnResult armazena o resultado do estabelecimento de uma conexão. Os
+// tempos dos handshakes TLS e HTTP CONNECT são medidos separadamente da conexão TCP.
+type ConnResult struct {
+	Dial      time.Duration // Tempo de estabelecimento da conexão TCP ou Unix.
+	TLS       time.Duration // Tempo do handshake TLS (zero sem TLS).
+	Handshake time.Duration // Tempo do handshake HTTP CONNECT (zero sem handshake).
+	Error     error         // Erro (se houver) ao conectar.
+	Reconnect bool          // Indica que a conexão substitui uma conexão perdida.
+	Retry     bool          // Indica uma nova tentativa após a falha da anterior.
+	GaveUp    bool          // Indica que Error é a falha da última tentativa, esgotadas as novas tentativas.
+}
+
+// MaxErrorMessages limita o número de mensagens de erro distintas contabilizadas;
+// as demais são agrupadas em OtherErrors.
+const MaxErrorMessages = 50
+
+// OtherErrors agrupa as mensagens de erro que excedem MaxErrorMessages.
+const OtherErrors = "outros erros"
+
+// Estrutura Options define a configuração dos histogramas e da série temporal do coletor.
+type Options struct {
+	Precision  int           // Algarismos significativos preservados (1 a 5).
+	MaxLatency time.Duration // Maior latência rastreável com a precisão configurada.
+	Interval   time.Duration // Largura dos intervalos da série temporal (zero desabilita).
 }
 
 // Estrutura Collector gerencia a coleta de métricas de todas as requisições.
 type Collector struct {
-	metrics Metrics // Armazena as métricas coletadas.
+	opts    Options        // Configuração dos histogramas.
+	metrics Metrics        // Armazena as métricas coletadas.
+	window  intervalWindow // Intervalo corrente da série temporal.
 }
 
 // Estrutura Metrics armazena os dados agregados das requisições.
 type Metrics struct {
-	TotalRequests int             // Número total de requisições.
-	Errors        int             // Número de requisições que falharam.
-	Durations     []time.Duration // Lista de durações das requisições bem-sucedidas.
-	StartTime     time.Time       // Timestamp de início da coleta de métricas.
-	EndTime       time.Time       // Timestamp de término da coleta de métricas.
+	TotalRequests int            // Número total de requisições.
+	Errors        int            // Número de requisições que falharam por erros de transporte ou do servidor.
+	ErrorCounts   map[string]int // Número de ocorrências de cada mensagem de erro.
+	Invalid       int            // Número de respostas recusadas pelos validadores.
+	InvalidCounts map[string]int // Número de ocorrências de cada falha de validação.
+	Categories    CategoryCounts // Falhas por categoria, incluindo as respostas inválidas.
+	Durations     *Histogram     // Histograma dos tempos de serviço das requisições bem-sucedidas.
+	ResponseTimes *Histogram     // Histograma dos tempos de resposta das requisições bem-sucedidas.
+	StartTime     time.Time      // Timestamp de início da coleta de métricas.
+	EndTime       time.Time      // Timestamp de término da coleta de métricas.
+	TargetRate    float64        // Taxa alvo em req/s (zero quando o teste não usa -rate).
+	Late          int            // Número de envios realizados com atraso em relação ao cronograma.
+	Dropped       int            // Número de envios agendados que foram descartados.
+	Stages        []StageMetrics // Métricas por estágio (vazio quando o teste não usa -stages).
+	Interrupted   bool           // Indica que o teste foi interrompido e as métricas são parciais.
+	Abandoned     int            // Número de chamadas em voo abandonadas na interrupção.
+	TimedOut      int            // Número de chamadas abandonadas em voo pelo timeout (também contabilizadas em Errors).
+	Connections   ConnMetrics    // Conexões estabelecidas pelos workers.
+	Retries       RetryMetrics   // Tentativas das chamadas com -retries.
+
+	Interval   time.Duration     // Largura dos intervalos da série temporal.
+	TimeSeries []IntervalMetrics // Série temporal de throughput, latência e erros.
+
+	Methods []MethodMetrics // Métricas por método (vazio quando o teste não usa -methods).
+}
+
+// Estrutura ConnMetrics armazena os dados agregados das conexões com o servidor.
+type ConnMetrics struct {
+	Attempts   int        // Número de tentativas de conexão.
+	Failures   int        // Número de tentativas que falharam.
+	Reconnects int        // Número de tentativas para substituir conexões perdidas.
+	Retries    int        // Número de novas tentativas após uma falha.
+	GiveUps    int        // Número de conexões abandonadas após esgotar as novas tentativas.
+	Dials      *Histogram // Tempos de conexão (TCP ou Unix) das tentativas bem-sucedidas.
+	TLS        *Histogram // Tempos do handshake TLS (vazio sem TLS).
+	Handshakes *Histogram // Tempos do handshake HTTP CONNECT (vazio sem handshake).
+}
+
+// Estrutura RetryMetrics armazena os dados agregados das tentativas das chamadas.
+type RetryMetrics struct {
+	Attempts int // Número de tentativas, incluindo as novas tentativas.
+	Retries  int // Número de novas tentativas após falhas de conexão.
+	GiveUps  int // Número de requisições que falharam após esgotar as novas tentativas.
+}
+
+// Estrutura StageMetrics armazena os dados agregados de um estágio do perfil de carga.
+type StageMetrics struct {
+	Name          string     // Descrição do estágio (ex: "rampa 0→500 em 30s").
+	TotalRequests int        // Número de requisições do estágio.
+	Errors        int        // Número de requisições do estágio que falharam, incluindo respostas inválidas.
+	Dropped       int        // Número de envios do estágio que foram descartados.
+	Durations     *Histogram // Tempos de serviço das requisições bem-sucedidas do estágio.
+	ResponseTimes *Histogram // Tempos de resposta das requisições bem-sucedidas do estágio.
+}
+
+// Estrutura MethodMetrics armazena os dados agregados de um método da mistura de tráfego.
+type MethodMetrics struct {
+	Name          string     // Nome do método RPC.
+	Weight        int        // Peso do método na mistura.
+	TotalRequests int        // Número de requisições do método.
+	Errors        int        // Número de requisições do método que falharam por erros de transporte ou do servidor.
+	Invalid       int        // Número de respostas do método recusadas pelos validadores.
+	Durations     *Histogram // Tempos de serviço das requisições bem-sucedidas do método.
+	ResponseTimes *Histogram // Tempos de resposta das requisições bem-sucedidas do método.
+}
+
+// Função NewCollector cria e inicializa uma nova instância de Collector. A memória
+// usada pelos histogramas é fixa e depende apenas da precisão e da latência máxima.
+func NewCollector(opts Options) *Collector {
+	c := &Collector{opts: opts}
+	c.metrics.Durations = c.newHistogram()     // Inicializa o histograma de durações vazio.
+	c.metrics.ResponseTimes = c.newHistogram() // Inicializa o histograma de tempos de resposta vazio.
+	c.metrics.Interval = opts.Interval
+	c.metrics.ErrorCounts = make(map[string]int)
+	c.metrics.InvalidCounts = make(map[string]int)
+	c.metrics.Categories = make(CategoryCounts)
+	c.metrics.Connections.Dials = c.newHistogram()
+	c.metrics.Connections.TLS = c.newHistogram()
+	c.metrics.Connections.Handshakes = c.newHistogram()
+	c.window.latency = c.newHistogram()
+	return c
 }
 
-// Função NewCollector cria e inicializa uma nova instância de Collector.
-func NewCollector() *Collector {
-	return &Collector{
-		metrics: Metrics{
-			Durations: make([]time.Duration, 0), // Inicializa a lista de durações vazia.
-		},
+// Método newHistogram cria um histograma com a configuração do coletor.
+func (c *Collector) newHistogram() *Histogram {
+	return NewHistogram(c.opts.Precision, c.opts.MaxLatency)
+}
+
+// Método Start marca o início da coleta de métricas.
+func (c *Collector) Start() {
+	c.metrics.StartTime = time.Now()
+}
+
+// Método Stop marca o término da coleta de métricas e fecha o último intervalo da série temporal.
+func (c *Collector) Stop() {
+	c.metrics.EndTime = time.Now()
+	if c.opts.Interval > 0 {
+		c.advanceIntervals(c.metrics.EndTime)
+		c.closeInterval()
+
+		// O último intervalo termina junto com o teste.
+		last := &c.metrics.TimeSeries[len(c.metrics.TimeSeries)-1]
+		last.Length = c.metrics.EndTime.Sub(c.metrics.StartTime) - last.Offset
+	}
+}
+
+// Método SetInterrupted marca o teste como interrompido antes do término planejado.
+func (c *Collector) SetInterrupted() {
+	c.metrics.Interrupted = true
+}
+
+// Método SetTargetRate registra a taxa alvo (req/s) configurada para o teste.
+func (c *Collector) SetTargetRate(rate float64) {
+	c.metrics.TargetRate = rate
+}
+
+// Método SetStages define os estágios do perfil de carga, habilitando as métricas por estágio.
+func (c *Collector) SetStages(names []string) {
+	c.metrics.Stages = make([]StageMetrics, len(names))
+	for i, name := range names {
+		c.metrics.Stages[i] = StageMetrics{
+			Name:          name,
+			Durations:     c.newHistogram(),
+			ResponseTimes: c.newHistogram(),
+		}
+	}
+}
+
+// Método SetMethods define os métodos da mistura de tráfego e seus pesos, habilitando
+// as métricas por método.
+func (c *Collector) SetMethods(names []string, weights []int) {
+	c.metrics.Methods = make([]MethodMetrics, len(names))
+	for i, name := range names {
+		c.metrics.Methods[i] = MethodMetrics{
+			Name:          name,
+			Weight:        weights[i],
+			Durations:     c.newHistogram(),
+			ResponseTimes: c.newHistogram(),
+		}
 	}
 }
 
 // Método RecordResult registra o resultado de uma requisição no coletor.
 func (c *Collector) RecordResult(result Result) {
+	if result.Conn != nil {
+		c.recordConnection(*result.Conn)
+		return
+	}
+
+	// Chamadas abandonadas não têm resultado conhecido; apenas são contabilizadas.
+	if result.Abandoned {
+		c.metrics.Abandoned++
+		return
+	}
+
+	c.recordStage(result)
+	c.recordInterval(result, time.Now())
+
+	// Envios descartados não chegaram a ser requisições; apenas são contabilizados.
+	if result.Dropped {
+		c.metrics.Dropped++
+		return
+	}
+
+	c.recordMethod(result)
 	c.metrics.TotalRequests++ // Incrementa o contador de requisições totais.
+	c.metrics.Retries.Attempts += 1 + result.Retries
+	c.metrics.Retries.Retries += result.Retries
+	if result.GaveUp {
+		c.metrics.Retries.GiveUps++ // Falhou em todas as tentativas.
+	}
+	if result.Late {
+		c.metrics.Late++ // Incrementa o contador de envios atrasados.
+	}
 
-	if result.Error != nil {
+	if result.Invalid {
+		c.metrics.Invalid++ // Resposta recebida, mas recusada pelos validadores.
+		recordMessage(c.metrics.InvalidCounts, result.Error)
+		c.recordCategory(result)
+	} else if result.Error != nil {
 		c.metrics.Errors++ // Incrementa o contador de erros se houver um erro.
+		recordMessage(c.metrics.ErrorCounts, result.Error)
+		c.recordCategory(result)
+		if result.TimedOut {
+			c.metrics.TimedOut++ // A chamada segue em voo no servidor após o timeout.
+		}
 	} else {
-		c.metrics.Durations = append(c.metrics.Durations, result.Duration) // Adiciona a duração à lista de durações.
+		c.metrics.Durations.Record(result.Duration)         // Registra a duração no histograma.
+		c.metrics.ResponseTimes.Record(result.ResponseTime) // Registra o tempo de resposta.
+	}
+}
+
+// Método recordConnection registra o estabelecimento de uma conexão.
+func (c *Collector) recordConnection(conn ConnResult) {
+	c.metrics.Connections.Attempts++
+	if conn.Reconnect {
+		c.metrics.Connections.Reconnects++
+	}
+	if conn.Retry {
+		c.metrics.Connections.Retries++
+	}
+	if conn.Error != nil {
+		c.metrics.Connections.Failures++
+		if conn.GaveUp {
+			c.metrics.Connections.GiveUps++
+		}
+		return
+	}
+	c.metrics.Connections.Dials.Record(conn.Dial)
+	if conn.TLS > 0 {
+		c.metrics.Connections.TLS.Record(conn.TLS)
+	}
+	if conn.Handshake > 0 {
+		c.metrics.Connections.Handshakes.Record(conn.Handshake)
+	}
+}
+
+// Função recordMessage contabiliza a mensagem de erro, limitando a cardinalidade.
+func recordMessage(counts map[string]int, err error) {
+	message := err.Error()
+	if _, known := counts[message]; !known && len(counts) >= MaxErrorMessages {
+		message = OtherErrors
+	}
+	counts[message]++
+}
+
+// Método recordStage acumula o resultado nas métricas do estágio correspondente.
+func (c *Collector) recordStage(result Result) {
+	if result.Stage < 0 || result.Stage >= len(c.metrics.Stages) {
+		return // Teste sem estágios ou índice fora do perfil.
+	}
+
+	stage := &c.metrics.Stages[result.Stage]
+	switch {
+	case result.Dropped:
+		stage.Dropped++
+	case result.Error != nil:
+		stage.TotalRequests++
+		stage.Errors++
+	default:
+		stage.TotalRequests++
+		stage.Durations.Record(result.Duration)
+		stage.ResponseTimes.Record(result.ResponseTime)
+	}
+}
+
+// Método recordMethod acumula o resultado nas métricas do método correspondente.
+func (c *Collector) recordMethod(result Result) {
+	if result.Method < 0 || result.Method >= len(c.metrics.Methods) {
+		return // Teste sem mistura de métodos ou índice fora da mistura.
+	}
+
+	method := &c.metrics.Methods[result.Method]
+	method.TotalRequests++
+	switch {
+	case result.Invalid:
+		method.Invalid++
+	case result.Error != nil:
+		method.Errors++
+	default:
+		method.Durations.Record(result.Duration)
+		method.ResponseTimes.Record(result.ResponseTime)
 	}
 }
 
@@ -56,37 +336,12 @@ func (c *Collector) GetMetrics() Metrics {
 	return metrics
 }
 
-// Método CalculatePercentile calcula o percentil das durações das requisições.
+// Método CalculatePercentile calcula o percentil (entre 0 e 1) das durações das requisições.
 func (c *Collector) CalculatePercentile(p float64) time.Duration {
-	if len(c.metrics.Durations) == 0 {
-		return 0 // Retorna 0 se não houver durações registradas.
-	}
-
-	// Ordena as durações em ordem crescente.
-	sort.Slice(c.metrics.Durations, func(i, j int) bool {
-		return c.metrics.Durations[i] < c.metrics.Durations[j]
-	})
-
-	// Calcula o índice correspondente ao percentil desejado.
-	index := int(float64(len(c.metrics.Durations)) * p)
-	if index >= len(c.metrics.Durations) {
-		index = len(c.metrics.Durations) - 1 // Garante que o índice não ultrapasse o tamanho da lista.
-	}
-	return c.metrics.Durations[index] // Retorna a duração no índice calculado.
+	return c.metrics.Durations.Percentile(p)
 }
 
 // Método AverageDuration calcula a duração média das requisições bem-sucedidas.
 func (c *Collector) AverageDuration() time.Duration {
-	if len(c.metrics.Durations) == 0 {
-		return 0 // Retorna 0 se não houver durações registradas.
-	}
-
-	// Soma todas as durações.
-	var total time.Duration
-	for _, d := range c.metrics.Durations {
-		total += d
-	}
-
-	// Retorna a média (soma das durações dividida pelo número de durações).
-	return total / time.Duration(len(c.metrics.Durations))
+	return c.metrics.Durations.Mean()
 }
@@ -0,0 +1,67 @@
+package metrics
+
+// Tipo ErrorCategory classifica a causa da falha de uma requisição.
+type ErrorCategory string
+
+// Categorias de falha das requisições.
+const (
+	ErrorDial       ErrorCategory = "dial"             // Falha ao estabelecer a conexão.
+	ErrorTimeout    ErrorCategory = "timeout"          // Chamada abandonada ao fim do timeout.
+	ErrorReset      ErrorCategory = "connection_reset" // Conexão perdida durante a chamada (reset, EOF).
+	ErrorServer     ErrorCategory = "server"           // Erro devolvido pelo servidor (rpc.ServerError, erro JSON-RPC).
+	ErrorValidation ErrorCategory = "validation"       // Resposta recusada pelos validadores.
+	ErrorShutdown   ErrorCategory = "shutdown"         // Chamada em uma conexão já encerrada (rpc.ErrShutdown).
+	ErrorUnknown    ErrorCategory = "unknown"          // Demais erros.
+)
+
+// ErrorCategories lista as categorias de falha na ordem de exibição dos relatórios.
+var ErrorCategories = []ErrorCategory{ErrorDial, ErrorTimeout, ErrorReset, ErrorServer, ErrorValidation, ErrorShutdown, ErrorUnknown}
+
+// Método Label devolve a descrição da categoria exibida nos relatórios.
+func (c ErrorCategory) Label() string {
+	switch c {
+	case ErrorDial:
+		return "falha de conexão"
+	case ErrorTimeout:
+		return "timeout"
+	case ErrorReset:
+		return "conexão perdida"
+	case ErrorServer:
+		return "erro do servidor"
+	case ErrorValidation:
+		return "resposta inválida"
+	case ErrorShutdown:
+		return "conexão encerrada"
+	default:
+		return "erro desconhecido"
+	}
+}
+
+// Tipo CategoryCounts agrupa as falhas de cada categoria.
+type CategoryCounts map[ErrorCategory]*CategoryMetrics
+
+// Estrutura CategoryMetrics armazena as falhas de uma categoria.
+type CategoryMetrics struct {
+	Count    int            // Número de requisições que falharam na categoria.
+	Messages map[string]int // Ocorrências de cada mensagem de erro, limitadas a MaxErrorMessages.
+}
+
+// Método recordCategory contabiliza a falha de uma requisição em sua categoria. Falhas
+// de validação pertencem sempre à categoria de validação e erros sem categoria, à de
+// erros desconhecidos.
+func (c *Collector) recordCategory(result Result) {
+	category := result.Category
+	if result.Invalid {
+		category = ErrorValidation
+	} else if category == "" {
+		category = ErrorUnknown
+	}
+
+	metrics, ok := c.metrics.Categories[category]
+	if !ok {
+		metrics = &CategoryMetrics{Messages: make(map[string]int)}
+		c.metrics.Categories[category] = metrics
+	}
+	metrics.Count++
+	recordMessage(metrics.Messages, result.Error)
+}
@@ -0,0 +1,39 @@
+package metrics
+
+// Importação de pacotes necessários.
+import (
+	"errors"  // Pacote para criar os erros das requisições sintéticas.
+	"testing" // Pacote de testes.
+	"time"    // Pacote para manipulação de tempo e durações.
+)
+
+func TestRecordCategory(t *testing.T) {
+	c := NewCollector(Options{Precision: 3, MaxLatency: time.Minute})
+	results := []Result{
+		{Error: errors.New("timeout após 1s"), Category: ErrorTimeout},
+		{Error: errors.New("timeout após 1s"), Category: ErrorTimeout},
+		{Error: errors.New("connection refused"), Category: ErrorDial},
+		{Error: errors.New("Result fora do intervalo"), Invalid: true},
+		{Error: errors.New("Result fora do intervalo"), Invalid: true, Category: ErrorServer},
+		{Error: errors.New("falha")}, // Sem categoria
+		{ResponseTime: time.Millisecond},
+	}
+	for _, r := range results {
+		c.RecordResult(r)
+	}
+
+	// Respostas inválidas contam sempre como validação; erros sem categoria, como desconhecidos
+	want := map[ErrorCategory]int{ErrorTimeout: 2, ErrorDial: 1, ErrorValidation: 2, ErrorUnknown: 1}
+	m := c.GetMetrics()
+	if len(m.Categories) != len(want) {
+		t.Errorf("%d categorias, esperadas %d", len(m.Categories), len(want))
+	}
+	for category, count := range want {
+		if got := m.Categories[category]; got == nil || got.Count != count {
+			t.Errorf("categoria %s = %+v, esperadas %d falhas", category, got, count)
+		}
+	}
+	if got := m.Categories[ErrorTimeout].Messages["timeout após 1s"]; got != 2 {
+		t.Errorf("%d ocorrências da mensagem de timeout, esperadas 2", got)
+	}
+}
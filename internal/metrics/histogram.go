@@ -0,0 +1,337 @@
+package metrics
+
+// Importação de pacotes necessários.
+import (
+	"encoding/binary" // Pacote para codificação compacta das contagens.
+	"encoding/json"   // Pacote para serialização do histograma.
+	"fmt"             // Pacote para formatação de mensagens de erro.
+	"math"            // Pacote para funções matemáticas.
+	"math/bits"       // Pacote para operações de bits.
+	"time"            // Pacote para manipulação de tempo e durações.
+)
+
+// Constantes que delimitam a configuração do histograma.
+const (
+	MinPrecision = 1 // Menor número de algarismos significativos suportado.
+	MaxPrecision = 5 // Maior número de algarismos significativos suportado.
+
+	// lowestDiscernible limita a menor unidade distinguível pelo histograma, que é a
+	// maior potência de dois em nanossegundos que não o excede (512ns). Valores abaixo
+	// de 2048 unidades (cerca de 1ms, com precisão 3) são registrados com essa resolução.
+	lowestDiscernible = int64(time.Microsecond)
+)
+
+// Estrutura Histogram implementa um histograma de alta faixa dinâmica (HDR) para
+// durações. Os valores são agrupados em baldes exponenciais subdivididos linearmente,
+// o que mantém o erro relativo abaixo de 10^-precisão usando memória fixa,
+// independente do número de valores registrados.
+type Histogram struct {
+	precision int   // Algarismos significativos preservados.
+	highest   int64 // Maior valor rastreável, em nanossegundos.
+
+	unitMagnitude               uint  // log2 da menor unidade distinguível.
+	subBucketHalfCountMagnitude uint  // log2 da metade do número de sub-baldes.
+	subBucketCount              int64 // Número de sub-baldes por balde.
+	subBucketHalfCount          int64 // Metade do número de sub-baldes.
+	subBucketMask               int64 // Máscara para o primeiro balde.
+
+	counts     []int64 // Contagens por sub-balde.
+	totalCount int64   // Número total de valores registrados.
+	min, max   int64   // Menor e maior valor registrados (exatos).
+	sum        float64 // Soma dos valores, para a média exata.
+	sumSquares float64 // Soma dos quadrados, para o desvio padrão.
+}
+
+// Função NewHistogram cria um histograma com a precisão (algarismos significativos,
+// de 1 a 5) e o maior valor rastreável informados. Valores acima do máximo são
+// registrados no último balde, mas Max continua exato.
+func NewHistogram(precision int, highest time.Duration) *Histogram {
+	if precision < MinPrecision {
+		precision = MinPrecision
+	}
+	if precision > MaxPrecision {
+		precision = MaxPrecision
+	}
+	if int64(highest) < 2*lowestDiscernible {
+		highest = time.Duration(2 * lowestDiscernible)
+	}
+
+	h := &Histogram{precision: precision, highest: int64(highest)}
+
+	// Número de sub-baldes necessário para resolução unitária até 2*10^precisão.
+	largestSingleUnit := 2 * math.Pow10(precision)
+	subBucketCountMagnitude := uint(math.Ceil(math.Log2(largestSingleUnit)))
+	h.subBucketHalfCountMagnitude = subBucketCountMagnitude - 1
+	h.unitMagnitude = uint(math.Floor(math.Log2(float64(lowestDiscernible))))
+	h.subBucketCount = int64(1) << subBucketCountMagnitude
+	h.subBucketHalfCount = h.subBucketCount / 2
+	h.subBucketMask = (h.subBucketCount - 1) << h.unitMagnitude
+
+	// Número de baldes exponenciais necessários para cobrir até o maior valor.
+	smallestUntrackable := h.subBucketCount << h.unitMagnitude
+	buckets := int64(1)
+	for smallestUntrackable < h.highest {
+		if smallestUntrackable > math.MaxInt64/2 {
+			buckets++
+			break
+		}
+		smallestUntrackable <<= 1
+		buckets++
+	}
+
+	h.counts = make([]int64, (buckets+1)*h.subBucketHalfCount)
+	h.Reset()
+	return h
+}
+
+// Método Record registra uma duração no histograma.
+func (h *Histogram) Record(d time.Duration) {
+	h.RecordN(d, 1)
+}
+
+// Método RecordN registra uma duração n vezes no histograma.
+func (h *Histogram) RecordN(d time.Duration, n int64) {
+	if n <= 0 {
+		return
+	}
+
+	v := int64(d)
+	if v < 0 {
+		v = 0
+	}
+
+	h.counts[h.countsIndexFor(min(v, h.highest))] += n
+	h.totalCount += n
+	h.min = min(h.min, v)
+	h.max = max(h.max, v)
+	h.sum += float64(v) * float64(n)
+	h.sumSquares += float64(v) * float64(v) * float64(n)
+}
+
+// Método Reset descarta todos os valores registrados, mantendo a configuração.
+func (h *Histogram) Reset() {
+	clear(h.counts)
+	h.totalCount = 0
+	h.min = math.MaxInt64
+	h.max = 0
+	h.sum = 0
+	h.sumSquares = 0
+}
+
+// Método Merge acumula no histograma os valores registrados em outro histograma.
+func (h *Histogram) Merge(other *Histogram) {
+	if other == nil || other.totalCount == 0 {
+		return
+	}
+
+	for i, count := range other.counts {
+		if count == 0 {
+			continue
+		}
+		v := other.highestEquivalentValue(other.valueFromIndex(i))
+		h.counts[h.countsIndexFor(min(v, h.highest))] += count
+	}
+	h.totalCount += other.totalCount
+	h.min = min(h.min, other.min)
+	h.max = max(h.max, other.max)
+	h.sum += other.sum
+	h.sumSquares += other.sumSquares
+}
+
+// Método Count retorna o número de valores registrados.
+func (h *Histogram) Count() int64 {
+	return h.totalCount
+}
+
+// Método Min retorna o menor valor registrado.
+func (h *Histogram) Min() time.Duration {
+	if h.totalCount == 0 {
+		return 0
+	}
+	return time.Duration(h.min)
+}
+
+// Método Max retorna o maior valor registrado.
+func (h *Histogram) Max() time.Duration {
+	return time.Duration(h.max)
+}
+
+// Método Mean retorna a média exata dos valores registrados.
+func (h *Histogram) Mean() time.Duration {
+	if h.totalCount == 0 {
+		return 0
+	}
+	return time.Duration(h.sum / float64(h.totalCount))
+}
+
+// Método StdDev retorna o desvio padrão populacional dos valores registrados.
+func (h *Histogram) StdDev() time.Duration {
+	if h.totalCount == 0 {
+		return 0
+	}
+	mean := h.sum / float64(h.totalCount)
+	variance := h.sumSquares/float64(h.totalCount) - mean*mean
+	return time.Duration(math.Sqrt(math.Max(variance, 0)))
+}
+
+// Método Percentile retorna o valor no percentil p (entre 0 e 1), com erro relativo
+// limitado pela precisão do histograma.
+func (h *Histogram) Percentile(p float64) time.Duration {
+	if h.totalCount == 0 {
+		return 0
+	}
+
+	p = math.Min(math.Max(p, 0), 1)
+	target := max(int64(p*float64(h.totalCount)+0.5), 1)
+
+	var seen int64
+	for i, count := range h.counts {
+		seen += count
+		if seen >= target {
+			v := h.highestEquivalentValue(h.valueFromIndex(i))
+			return time.Duration(min(max(v, h.min), h.max))
+		}
+	}
+	return time.Duration(h.max)
+}
+
+// Método ForEach percorre os valores registrados em ordem crescente, informando o
+// maior valor equivalente de cada sub-balde e sua contagem.
+func (h *Histogram) ForEach(fn func(value time.Duration, count int64)) {
+	for i, count := range h.counts {
+		if count != 0 {
+			v := h.highestEquivalentValue(h.valueFromIndex(i))
+			fn(time.Duration(min(max(v, h.min), h.max)), count)
+		}
+	}
+}
+
+// Método Precision retorna o número de algarismos significativos do histograma.
+func (h *Histogram) Precision() int {
+	return h.precision
+}
+
+// Método Highest retorna o maior valor rastreável do histograma.
+func (h *Histogram) Highest() time.Duration {
+	return time.Duration(h.highest)
+}
+
+// Método Clone retorna uma cópia independente do histograma.
+func (h *Histogram) Clone() *Histogram {
+	clone := *h
+	clone.counts = make([]int64, len(h.counts))
+	copy(clone.counts, h.counts)
+	return &clone
+}
+
+// Estrutura histogramJSON define o formato serializado do histograma. As contagens
+// não nulas são gravadas de forma compacta como pares de varints (distância até o
+// índice anterior, contagem), codificados em base64.
+type histogramJSON struct {
+	Precision  int     `json:"precision"`
+	Highest    int64   `json:"highest_ns"`
+	Count      int64   `json:"count"`
+	Min        int64   `json:"min_ns"`
+	Max        int64   `json:"max_ns"`
+	Sum        float64 `json:"sum_ns"`
+	SumSquares float64 `json:"sum_squares_ns2"`
+	Counts     []byte  `json:"counts"`
+}
+
+// Método MarshalJSON serializa o histograma em formato esparso.
+func (h *Histogram) MarshalJSON() ([]byte, error) {
+	out := histogramJSON{
+		Precision:  h.precision,
+		Highest:    h.highest,
+		Count:      h.totalCount,
+		Min:        int64(h.Min()),
+		Max:        h.max,
+		Sum:        h.sum,
+		SumSquares: h.sumSquares,
+		Counts:     make([]byte, 0),
+	}
+
+	previous := 0
+	for i, count := range h.counts {
+		if count != 0 {
+			out.Counts = binary.AppendUvarint(out.Counts, uint64(i-previous))
+			out.Counts = binary.AppendUvarint(out.Counts, uint64(count))
+			previous = i
+		}
+	}
+	return json.Marshal(out)
+}
+
+// Método UnmarshalJSON reconstrói o histograma a partir do formato esparso.
+func (h *Histogram) UnmarshalJSON(data []byte) error {
+	var in histogramJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	*h = *NewHistogram(in.Precision, time.Duration(in.Highest))
+	index := uint64(0)
+	for data := in.Counts; len(data) > 0; {
+		delta, n := binary.Uvarint(data)
+		if n <= 0 {
+			return fmt.Errorf("histograma: contagens corrompidas")
+		}
+		count, m := binary.Uvarint(data[n:])
+		if m <= 0 {
+			return fmt.Errorf("histograma: contagens corrompidas")
+		}
+		data = data[n+m:]
+
+		index += delta
+		if index >= uint64(len(h.counts)) {
+			return fmt.Errorf("histograma: índice %d fora da faixa", index)
+		}
+		h.counts[index] = int64(count)
+	}
+	h.totalCount = in.Count
+	if in.Count > 0 {
+		h.min = in.Min
+	}
+	h.max = in.Max
+	h.sum = in.Sum
+	h.sumSquares = in.SumSquares
+	return nil
+}
+
+// Método countsIndexFor calcula a posição do valor no vetor de contagens.
+func (h *Histogram) countsIndexFor(v int64) int {
+	bucket := h.bucketIndex(v)
+	subBucket := v >> (uint(bucket) + h.unitMagnitude)
+	base := int64(bucket+1) << h.subBucketHalfCountMagnitude
+	return int(base + subBucket - h.subBucketHalfCount)
+}
+
+// Método bucketIndex calcula o balde exponencial de um valor.
+func (h *Histogram) bucketIndex(v int64) int {
+	pow2Ceiling := 64 - bits.LeadingZeros64(uint64(v|h.subBucketMask))
+	return pow2Ceiling - int(h.unitMagnitude) - int(h.subBucketHalfCountMagnitude+1)
+}
+
+// Método valueFromIndex retorna o menor valor equivalente a uma posição de contagem.
+func (h *Histogram) valueFromIndex(i int) int64 {
+	bucket := (int64(i) >> h.subBucketHalfCountMagnitude) - 1
+	subBucket := (int64(i) & (h.subBucketHalfCount - 1)) + h.subBucketHalfCount
+	if bucket < 0 {
+		subBucket -= h.subBucketHalfCount
+		bucket = 0
+	}
+	return subBucket << (uint(bucket) + h.unitMagnitude)
+}
+
+// Método highestEquivalentValue retorna o maior valor indistinguível de v.
+func (h *Histogram) highestEquivalentValue(v int64) int64 {
+	bucket := h.bucketIndex(v)
+	subBucket := v >> (uint(bucket) + h.unitMagnitude)
+	lowest := subBucket << (uint(bucket) + h.unitMagnitude)
+
+	// O último sub-balde de cada balde tem a largura do balde seguinte.
+	if subBucket >= h.subBucketCount {
+		bucket++
+	}
+	return lowest + int64(1)<<(h.unitMagnitude+uint(bucket)) - 1
+}
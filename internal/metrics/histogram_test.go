@@ -0,0 +1,131 @@
+package metrics
+
+// Importação de pacotes necessários.
+import (
+	"math"    // Pacote para o cálculo das tolerâncias.
+	"testing" // Pacote de testes.
+	"time"    // Pacote para manipulação de tempo e durações.
+)
+
+func TestHistogramPrecision(t *testing.T) {
+	values := []time.Duration{
+		time.Microsecond, 37 * time.Microsecond, 999 * time.Microsecond, 2047 * time.Microsecond,
+		12345 * time.Microsecond, 250 * time.Millisecond, 3333 * time.Millisecond, 59 * time.Second,
+	}
+	for precision := MinPrecision; precision <= MaxPrecision; precision++ {
+		for _, v := range values {
+			h := NewHistogram(precision, time.Minute)
+			h.Record(v)
+			h.Record(time.Minute) // Sem o segundo valor, Percentile devolveria Min/Max exatos
+
+			got := h.Percentile(0)
+			tolerance := max(float64(v)*math.Pow10(-precision), float64(lowestDiscernible))
+			if diff := math.Abs(float64(got - v)); diff > tolerance {
+				t.Errorf("precisão %d: %v registrado como %v (erro %v, tolerância %v)", precision, v, got, time.Duration(diff), time.Duration(tolerance))
+			}
+		}
+	}
+}
+
+func TestHistogramStats(t *testing.T) {
+	h := NewHistogram(3, time.Minute)
+	if h.Count() != 0 || h.Min() != 0 || h.Max() != 0 || h.Mean() != 0 || h.Percentile(0.99) != 0 {
+		t.Error("histograma vazio deveria devolver zeros")
+	}
+
+	for i := 1; i <= 1000; i++ {
+		h.Record(time.Duration(i) * time.Millisecond)
+	}
+	h.Record(2 * time.Minute) // Acima do máximo rastreável: Max continua exato
+
+	tests := []struct {
+		name string
+		got  time.Duration
+		want time.Duration
+	}{
+		{"min", h.Min(), time.Millisecond},
+		{"max", h.Max(), 2 * time.Minute},
+		{"p50", h.Percentile(0.5), 501 * time.Millisecond},
+		{"p90", h.Percentile(0.9), 901 * time.Millisecond},
+		{"p99", h.Percentile(0.99), 991 * time.Millisecond},
+		{"p100", h.Percentile(1), time.Minute}, // Valores acima do máximo ficam no último balde
+	}
+	for _, tt := range tests {
+		if diff := math.Abs(float64(tt.got - tt.want)); diff > float64(tt.want)/1000 {
+			t.Errorf("%s = %v, esperado %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if h.Count() != 1001 {
+		t.Errorf("Count = %d, esperado 1001", h.Count())
+	}
+}
+
+func TestHistogramMerge(t *testing.T) {
+	tests := []struct {
+		name        string
+		left, right []time.Duration
+	}{
+		{"disjuntos", []time.Duration{time.Millisecond, 2 * time.Millisecond}, []time.Duration{time.Second, 3 * time.Second}},
+		{"sobrepostos", []time.Duration{5 * time.Millisecond, 7 * time.Millisecond}, []time.Duration{6 * time.Millisecond, 5 * time.Millisecond}},
+		{"à direita vazio", []time.Duration{time.Millisecond}, nil},
+		{"à esquerda vazio", nil, []time.Duration{42 * time.Microsecond}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			left, right, all := NewHistogram(3, time.Minute), NewHistogram(3, time.Minute), NewHistogram(3, time.Minute)
+			for _, v := range tt.left {
+				left.Record(v)
+				all.Record(v)
+			}
+			for _, v := range tt.right {
+				right.Record(v)
+				all.Record(v)
+			}
+
+			left.Merge(right)
+			if left.Count() != all.Count() || left.Min() != all.Min() || left.Max() != all.Max() || left.Mean() != all.Mean() {
+				t.Errorf("mesclado: count %d, min %v, max %v, média %v; esperado %d, %v, %v, %v",
+					left.Count(), left.Min(), left.Max(), left.Mean(), all.Count(), all.Min(), all.Max(), all.Mean())
+			}
+			for _, p := range []float64{0, 0.5, 0.9, 0.99, 1} {
+				if got, want := left.Percentile(p), all.Percentile(p); got != want {
+					t.Errorf("p%g mesclado = %v, esperado %v", p*100, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHistogramMergePrecisions(t *testing.T) {
+	// Valores de um histograma menos preciso são reagrupados sem sair da tolerância dele
+	coarse, fine := NewHistogram(2, time.Minute), NewHistogram(4, time.Minute)
+	coarse.Record(123456 * time.Microsecond)
+	fine.Merge(coarse)
+	fine.Merge(nil)
+
+	if got := fine.Percentile(0.5); math.Abs(float64(got-123456*time.Microsecond)) > float64(123456*time.Microsecond)/100 {
+		t.Errorf("valor mesclado = %v", got)
+	}
+	if fine.Count() != 1 {
+		t.Errorf("Count = %d, esperado 1", fine.Count())
+	}
+}
+
+func TestHistogramJSON(t *testing.T) {
+	h := NewHistogram(3, time.Minute)
+	for _, v := range []time.Duration{time.Millisecond, 5 * time.Millisecond, 5 * time.Millisecond, time.Second} {
+		h.Record(v)
+	}
+	data, err := h.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Histogram
+	if err := decoded.UnmarshalJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Count() != h.Count() || decoded.Min() != h.Min() || decoded.Max() != h.Max() || decoded.Percentile(0.5) != h.Percentile(0.5) {
+		t.Errorf("histograma decodificado difere do original: %s", data)
+	}
+}
@@ -0,0 +1,73 @@
+package metrics
+
+// Importação de pacotes necessários.
+import (
+	"time" // Pacote para manipulação de tempo e durações.
+)
+
+// Estrutura IntervalMetrics armazena os dados agregados de um intervalo da série
+// temporal. A latência considerada é o tempo de resposta, atribuído ao intervalo em
+// que a requisição terminou.
+type IntervalMetrics struct {
+	Offset   time.Duration // Início do intervalo em relação ao início do teste.
+	Length   time.Duration // Largura do intervalo (o último pode ser parcial).
+	Requests int           // Número de requisições concluídas no intervalo.
+	Errors   int           // Número de requisições do intervalo que falharam, incluindo respostas inválidas.
+	Dropped  int           // Número de envios descartados no intervalo.
+	P50      time.Duration // Mediana do tempo de resposta.
+	P90      time.Duration // Percentil 90 do tempo de resposta.
+	P99      time.Duration // Percentil 99 do tempo de resposta.
+	Max      time.Duration // Maior tempo de resposta.
+}
+
+// Estrutura intervalWindow acumula o intervalo corrente da série temporal. Apenas um
+// histograma é mantido: ao fechar o intervalo os percentis são extraídos e ele é
+// reaproveitado, de modo que a memória cresce só com o número de intervalos.
+type intervalWindow struct {
+	index   int             // Índice do intervalo corrente.
+	current IntervalMetrics // Contadores do intervalo corrente.
+	latency *Histogram      // Tempos de resposta do intervalo corrente.
+}
+
+// Método recordInterval acumula o resultado no intervalo em que ele foi registrado.
+func (c *Collector) recordInterval(result Result, now time.Time) {
+	if c.opts.Interval <= 0 {
+		return // Série temporal desabilitada.
+	}
+
+	c.advanceIntervals(now)
+	switch {
+	case result.Dropped:
+		c.window.current.Dropped++
+	case result.Error != nil:
+		c.window.current.Requests++
+		c.window.current.Errors++
+	default:
+		c.window.current.Requests++
+		c.window.latency.Record(result.ResponseTime)
+	}
+}
+
+// Método advanceIntervals fecha os intervalos que já terminaram, incluindo os vazios.
+func (c *Collector) advanceIntervals(now time.Time) {
+	index := int(now.Sub(c.metrics.StartTime) / c.opts.Interval)
+	for c.window.index < index {
+		c.closeInterval()
+	}
+}
+
+// Método closeInterval consolida o intervalo corrente na série temporal e inicia o próximo.
+func (c *Collector) closeInterval() {
+	w := &c.window
+	w.current.Offset = time.Duration(w.index) * c.opts.Interval
+	w.current.Length = c.opts.Interval
+	w.current.P50 = w.latency.Percentile(0.5)
+	w.current.P90 = w.latency.Percentile(0.9)
+	w.current.P99 = w.latency.Percentile(0.99)
+	w.current.Max = w.latency.Max()
+	c.metrics.TimeSeries = append(c.metrics.TimeSeries, w.current)
+
+	w.index++
+	w.current = IntervalMetrics{}
+	w.latency.Reset()
+}
@@ -0,0 +1,88 @@
+package metrics
+
+// Importação de pacotes necessários.
+import (
+	"errors"  // Pacote para criar os erros das requisições sintéticas.
+	"testing" // Pacote de testes.
+	"time"    // Pacote para manipulação de tempo e durações.
+)
+
+func TestTimeSeriesIntervals(t *testing.T) {
+	c := NewCollector(Options{Precision: 3, MaxLatency: time.Minute, Interval: time.Second})
+	start := time.Now()
+	c.metrics.StartTime = start
+
+	// Resultados nos intervalos 0, 0, 0, 2 e 3; o intervalo 1 fica vazio
+	results := []struct {
+		at     time.Duration
+		result Result
+	}{
+		{100 * time.Millisecond, Result{ResponseTime: 10 * time.Millisecond}},
+		{200 * time.Millisecond, Result{ResponseTime: 30 * time.Millisecond}},
+		{999 * time.Millisecond, Result{Error: errors.New("falha")}},
+		{2500 * time.Millisecond, Result{Dropped: true}},
+		{3 * time.Second, Result{ResponseTime: 5 * time.Millisecond}},
+	}
+	for _, r := range results {
+		c.recordInterval(r.result, start.Add(r.at))
+	}
+	c.advanceIntervals(start.Add(3200 * time.Millisecond))
+	c.closeInterval()
+
+	want := []IntervalMetrics{
+		{Offset: 0, Requests: 3, Errors: 1, Max: 30 * time.Millisecond},
+		{Offset: time.Second},
+		{Offset: 2 * time.Second, Dropped: 1},
+		{Offset: 3 * time.Second, Requests: 1, Max: 5 * time.Millisecond},
+	}
+	if len(c.metrics.TimeSeries) != len(want) {
+		t.Fatalf("%d intervalos, esperados %d", len(c.metrics.TimeSeries), len(want))
+	}
+	for i, w := range want {
+		got := c.metrics.TimeSeries[i]
+		if got.Offset != w.Offset || got.Length != time.Second || got.Requests != w.Requests || got.Errors != w.Errors || got.Dropped != w.Dropped {
+			t.Errorf("intervalo %d = %+v, esperado %+v", i, got, w)
+		}
+		// A latência de cada intervalo considera apenas as suas requisições bem-sucedidas
+		if got.Max != w.Max || got.P50 > got.Max {
+			t.Errorf("intervalo %d: p50 %v, max %v; esperado max %v", i, got.P50, got.Max, w.Max)
+		}
+	}
+}
+
+func TestTimeSeriesStop(t *testing.T) {
+	c := NewCollector(Options{Precision: 3, MaxLatency: time.Minute, Interval: 20 * time.Millisecond})
+	c.Start()
+	c.RecordResult(Result{ResponseTime: time.Millisecond})
+	time.Sleep(50 * time.Millisecond)
+	c.RecordResult(Result{ResponseTime: time.Millisecond})
+	c.Stop()
+
+	m := c.GetMetrics()
+	if len(m.TimeSeries) < 3 {
+		t.Fatalf("%d intervalos em 50ms com intervalos de 20ms", len(m.TimeSeries))
+	}
+	requests := 0
+	for _, interval := range m.TimeSeries {
+		requests += interval.Requests
+	}
+	if requests != 2 {
+		t.Errorf("%d requisições na série temporal, esperadas 2", requests)
+	}
+
+	// O último intervalo termina junto com o teste
+	last := m.TimeSeries[len(m.TimeSeries)-1]
+	if last.Offset+last.Length != m.EndTime.Sub(m.StartTime) || last.Length > m.Interval {
+		t.Errorf("último intervalo %v+%v, teste de %v", last.Offset, last.Length, m.EndTime.Sub(m.StartTime))
+	}
+}
+
+func TestTimeSeriesDisabled(t *testing.T) {
+	c := NewCollector(Options{Precision: 3, MaxLatency: time.Minute})
+	c.Start()
+	c.RecordResult(Result{ResponseTime: time.Millisecond})
+	c.Stop()
+	if m := c.GetMetrics(); len(m.TimeSeries) != 0 {
+		t.Errorf("série temporal com %d intervalos sem -interval", len(m.TimeSeries))
+	}
+}
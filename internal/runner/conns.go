@@ -0,0 +1,173 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"github.com/denner-s/gorpcstress/internal/config"
+	"github.com/denner-s/gorpcstress/internal/metrics"
+	"github.com/denner-s/gorpcstress/pkg/rpcclient"
+	"log"
+	"net/rpc"
+	"sync"
+	"sync/atomic"
+)
+
+// connSession fornece as conexões usadas por um worker conforme -conn-mode: a conexão
+// própria do worker (worker), uma das conexões compartilhadas (pool) ou uma conexão
+// nova a cada chamada (request). Conexões perdidas são restabelecidas na chamada seguinte,
+// a menos que -reconnect esteja desativado.
+type connSession struct {
+	sr      *StressRunner
+	results chan<- metrics.Result
+	client  rpcclient.Caller // Conexão própria do worker (modo worker)
+	dialed  bool             // Indica que a sessão já conectou; as próximas conexões são reconexões
+	worker  int              // Número do worker dono da sessão, a partir de 1 (gerador {{worker}})
+}
+
+// newSession cria a sessão de conexões de um worker
+func (sr *StressRunner) newSession(results chan<- metrics.Result) *connSession {
+	return &connSession{sr: sr, results: results, worker: int(sr.workers.Add(1))}
+}
+
+// acquire devolve a conexão da próxima chamada e a função que a libera, chamada com o
+// erro da chamada ao seu término
+func (s *connSession) acquire(ctx context.Context) (rpcclient.Caller, func(error), error) {
+	switch s.sr.cfg.ConnMode {
+	case config.ConnModePool:
+		return s.sr.pool.acquire(ctx, s.sr, s.results)
+	case config.ConnModeRequest:
+		client, err := s.sr.dial(ctx, s.results, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		return client, func(error) { closeClient(client) }, nil
+	}
+
+	if s.client == nil {
+		if s.dialed && !s.sr.cfg.Reconnect {
+			return nil, nil, errReconnectDisabled
+		}
+		client, err := s.sr.dial(ctx, s.results, s.dialed)
+		if err != nil {
+			return nil, nil, err
+		}
+		s.client, s.dialed = client, true
+	}
+	client := s.client
+	return client, func(err error) {
+		if !connectionLost(err) {
+			return
+		}
+		// Chamadas pendentes da conexão perdida não descartam a conexão que a substituiu
+		if s.client == client {
+			closeClient(client)
+			s.client = nil
+		}
+	}, nil
+}
+
+// warmup estabelece antecipadamente a conexão da sessão, para que o tempo de conexão
+// não atrase o cronograma dos modos de malha aberta. No modo request não há o que preparar.
+func (s *connSession) warmup(ctx context.Context) error {
+	if s.sr.cfg.ConnMode == config.ConnModeRequest {
+		return nil
+	}
+	_, release, err := s.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	release(nil)
+	return nil
+}
+
+// close fecha a conexão própria da sessão; as conexões do pool são fechadas pelo runner
+func (s *connSession) close() {
+	if s.client != nil {
+		closeClient(s.client)
+		s.client = nil
+	}
+}
+
+// connPool é o conjunto de conexões compartilhadas pelos workers no modo pool. O
+// rpc.Client multiplexa chamadas concorrentes, que são distribuídas em rodízio.
+type connPool struct {
+	slots []poolSlot
+	next  atomic.Uint64
+}
+
+// poolSlot guarda uma conexão do pool, aberta na primeira chamada que a utiliza
+type poolSlot struct {
+	mu     sync.Mutex
+	client rpcclient.Caller
+	dialed bool
+}
+
+// newConnPool cria um pool vazio com o número de conexões informado
+func newConnPool(size int) *connPool {
+	return &connPool{slots: make([]poolSlot, size)}
+}
+
+// acquire seleciona a próxima conexão do rodízio, conectando-a se necessário
+func (p *connPool) acquire(ctx context.Context, sr *StressRunner, results chan<- metrics.Result) (rpcclient.Caller, func(error), error) {
+	slot := &p.slots[p.next.Add(1)%uint64(len(p.slots))]
+
+	slot.mu.Lock()
+	if slot.client == nil {
+		if slot.dialed && !sr.cfg.Reconnect {
+			slot.mu.Unlock()
+			return nil, nil, errReconnectDisabled
+		}
+		client, err := sr.dial(ctx, results, slot.dialed)
+		if err != nil {
+			slot.mu.Unlock()
+			return nil, nil, err
+		}
+		slot.client, slot.dialed = client, true
+	}
+	client := slot.client
+	slot.mu.Unlock()
+
+	return client, func(err error) {
+		if !connectionLost(err) {
+			return
+		}
+		// Apenas a primeira chamada a perceber a perda descarta a conexão
+		slot.mu.Lock()
+		if slot.client == client {
+			closeClient(client)
+			slot.client = nil
+		}
+		slot.mu.Unlock()
+	}, nil
+}
+
+// close fecha todas as conexões do pool
+func (p *connPool) close() {
+	for i := range p.slots {
+		if p.slots[i].client != nil {
+			closeClient(p.slots[i].client)
+			p.slots[i].client = nil
+		}
+	}
+}
+
+// connectionLost indica se o erro de uma chamada significa que a conexão foi perdida,
+// inclusive quando fechada abruptamente pelo servidor (connection reset, broken pipe)
+func connectionLost(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch classifyError(err) {
+	case metrics.ErrorReset, metrics.ErrorShutdown:
+		return true
+	}
+	return false
+}
+
+// closeClient fecha uma conexão, registrando eventuais erros; conexões já perdidas
+// não são consideradas erro
+func closeClient(client rpcclient.Caller) {
+	if err := client.Close(); err != nil && !errors.Is(err, rpc.ErrShutdown) {
+		log.Printf("Erro ao fechar cliente: %v", err)
+	}
+}
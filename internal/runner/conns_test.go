@@ -0,0 +1,102 @@
+package runner
+
+import (
+	"context"
+	"github.com/denner-s/gorpcstress/internal/config"
+	"net"
+	"net/rpc"
+	"testing"
+)
+
+func TestConnModes(t *testing.T) {
+	tests := []struct {
+		mode  string
+		conns int64 // Conexões esperadas no servidor
+	}{
+		{config.ConnModeWorker, 4},
+		{config.ConnModePool, 2},
+		{config.ConnModeRequest, 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			ts := startServer(t, 0)
+			cfg := testConfig(ts.addr)
+			cfg.ConnMode, cfg.PoolSize = tt.mode, 2
+			collector := newCollector()
+			NewStressRunner(cfg, collector).Run(context.Background())
+
+			m := collector.GetMetrics()
+			if m.TotalRequests != 20 || m.Errors != 0 {
+				t.Fatalf("%d requisições e %d erros; esperadas 20 sem erros", m.TotalRequests, m.Errors)
+			}
+			if got := ts.conns.Load(); got != tt.conns {
+				t.Errorf("servidor recebeu %d conexões, esperadas %d", got, tt.conns)
+			}
+			if c := m.Connections; int64(c.Attempts) != tt.conns || c.Reconnects != 0 {
+				t.Errorf("%d conexões e %d reconexões medidas; esperadas %d sem reconexões", c.Attempts, c.Reconnects, tt.conns)
+			}
+		})
+	}
+}
+
+// startDroppingServer inicia um servidor Arithmetic que fecha a primeira conexão
+// recebida sem atender chamadas
+func startDroppingServer(t *testing.T) string {
+	t.Helper()
+	server := rpc.NewServer()
+	if err := server.RegisterName("Arithmetic", &testArithmetic{}); err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for first := true; ; first = false {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			if first {
+				_ = conn.Close()
+				continue
+			}
+			go server.ServeConn(conn)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestReconnectAfterLostConnection(t *testing.T) {
+	tests := []struct {
+		name      string
+		reconnect bool
+		retries   int
+		errors    int // Chamadas com erro
+		conns     int // Conexões abertas
+	}{
+		{"sem reconexão", false, 0, 4, 1},
+		{"com reconexão", true, 0, 1, 2},
+		{"com nova tentativa", true, 1, 0, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(startDroppingServer(t))
+			cfg.TotalRequests, cfg.Concurrency = 4, 1
+			cfg.Reconnect, cfg.Retries = tt.reconnect, tt.retries
+			collector := newCollector()
+			NewStressRunner(cfg, collector).Run(context.Background())
+
+			// A chamada na conexão perdida falha; sem reconexão, as seguintes também
+			m := collector.GetMetrics()
+			if m.TotalRequests != 4 || m.Errors != tt.errors || m.Retries.Retries != tt.retries {
+				t.Errorf("%d requisições, %d erros e %d novas tentativas; esperadas 4 com %d erros e %d novas tentativas", m.TotalRequests, m.Errors, m.Retries.Retries, tt.errors, tt.retries)
+			}
+			if c := m.Connections; c.Attempts != tt.conns || c.Reconnects != tt.conns-1 || c.Failures != 0 {
+				t.Errorf("%d conexões, %d reconexões e %d falhas; esperadas %d conexões", c.Attempts, c.Reconnects, c.Failures, tt.conns)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package runner
+
+import (
+	"fmt"
+	"github.com/denner-s/gorpcstress/internal/config"
+	"github.com/denner-s/gorpcstress/pkg/rpcclient"
+	"github.com/denner-s/gorpcstress/pkg/validate"
+	"log"
+	"math/rand/v2"
+	"os"
+	"slices"
+)
+
+// method reúne o payload e os validadores de um método da mistura de tráfego
+type method struct {
+	index      int                  // Posição do método na mistura, usada nas métricas por método
+	name       string               // Nome do método RPC
+	payload    *rpcclient.Payload   // Argumentos e resposta esperada das chamadas
+	validators []validate.Validator // Validadores das respostas do método
+}
+
+// CheckMethods carrega os payloads, os modelos e os validadores de cada método da
+// mistura sem executar o teste. O erro devolvido é do tipo config.Problems e reúne
+// todos os problemas encontrados.
+func CheckMethods(cfg *config.Config) error {
+	_, err := buildMethods(cfg)
+	return err
+}
+
+// loadMethods carrega o payload e os validadores de cada método da mistura. Com
+// -methods, os métodos também são registrados no coletor para as métricas por método.
+func (sr *StressRunner) loadMethods() {
+	methods, err := buildMethods(sr.cfg)
+	if err != nil {
+		log.Fatalf("Configuração inválida: %v", err)
+	}
+	sr.methods = methods
+
+	names := make([]string, len(methods))
+	weights := make([]int, len(methods))
+	total := 0
+	for i, spec := range sr.cfg.Methods() {
+		total += spec.Weight
+		sr.cumWeights = append(sr.cumWeights, total)
+		names[i], weights[i] = spec.Method, spec.Weight
+	}
+
+	if len(sr.cfg.MethodMix) > 0 {
+		sr.metrics.SetMethods(names, weights)
+	}
+}
+
+// buildMethods cria os métodos da mistura com seus payloads e validadores, reunindo
+// os problemas de todos eles
+func buildMethods(cfg *config.Config) ([]*method, error) {
+	var methods []*method
+	var problems config.Problems
+	for i, spec := range cfg.Methods() {
+		m := &method{index: i, name: spec.Method}
+		// Métodos sem payload próprio usam o de -payload, se informado
+		path := spec.PayloadFile
+		if path == "" {
+			path = cfg.PayloadFile
+		}
+		if path != "" {
+			payload, err := loadPayload(path)
+			if err != nil {
+				problems = append(problems, err)
+				continue
+			}
+			m.payload = payload
+		} else {
+			// Valores padrão que correspondem ao exemplo do servidor
+			m.payload = rpcclient.DefaultPayload()
+		}
+
+		validators, errs := loadValidators(cfg, m)
+		problems = append(problems, errs...)
+		m.validators = validators
+		methods = append(methods, m)
+	}
+	return methods, problems.Err()
+}
+
+// nextRequest numera uma nova requisição do worker e sorteia seu método. A fonte
+// aleatória da requisição, derivada da semente e do número, também gera os valores
+// dos modelos do payload: com a mesma semente, cada número de requisição recebe sempre
+// o mesmo método e os mesmos valores.
+func (sr *StressRunner) nextRequest(worker int) (*method, *rpcclient.Generation) {
+	seq := sr.requests.Add(1)
+	gen := &rpcclient.Generation{
+		Seq:    seq,
+		Worker: worker,
+		Rand:   rand.New(rand.NewPCG(uint64(sr.cfg.Seed), uint64(seq))),
+	}
+	return sr.pickMethod(gen.Rand), gen
+}
+
+// nextMethod numera uma requisição que não chegou a ser enviada e sorteia seu método
+func (sr *StressRunner) nextMethod() *method {
+	m, _ := sr.nextRequest(0)
+	return m
+}
+
+// pickMethod sorteia o método de uma requisição com probabilidade proporcional ao peso
+func (sr *StressRunner) pickMethod(r *rand.Rand) *method {
+	if len(sr.methods) == 1 {
+		return sr.methods[0]
+	}
+	// cumWeights acumula os pesos: o método sorteado é o primeiro cujo acumulado excede n
+	n := r.IntN(sr.cumWeights[len(sr.cumWeights)-1])
+	i, _ := slices.BinarySearch(sr.cumWeights, n+1)
+	return sr.methods[i]
+}
+
+// loadPayload carrega dados de chamada de um arquivo JSON
+func loadPayload(path string) (*rpcclient.Payload, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao abrir arquivo de payload: %w", err)
+	}
+	defer func(file *os.File) {
+		if err := file.Close(); err != nil {
+			log.Printf("Erro ao fechar arquivo: %v", err)
+		}
+	}(file)
+
+	payload, err := rpcclient.LoadPayload(file)
+	if err != nil {
+		return nil, fmt.Errorf("erro na decodificação do payload %s: %w", path, err)
+	}
+	return payload, nil
+}
+
+// loadValidators cria os validadores configurados para o método. Sem validadores
+// configurados, a resposta é comparada com o valor esperado do payload, se houver.
+func loadValidators(cfg *config.Config, m *method) ([]validate.Validator, []error) {
+	specs := cfg.ValidatorsFor(m.name)
+	if len(specs) == 0 && m.payload.Expected() != nil {
+		specs = []string{"equals"}
+	}
+
+	var validators []validate.Validator
+	var errs []error
+	for _, spec := range specs {
+		// Sem o formato da resposta no payload ela é descartada e não há o que validar
+		if spec != "none" && m.payload.NewReply() == nil {
+			errs = append(errs, fmt.Errorf("validador %q de %s exige \"reply\" ou \"expected\" no arquivo de payload", spec, m.name))
+			continue
+		}
+
+		v, err := validate.Parse(spec, m.payload.Expected())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("validador inválido %q de %s: %w", spec, m.name, err))
+			continue
+		}
+		validators = append(validators, v)
+	}
+	return validators, errs
+}
@@ -0,0 +1,80 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"github.com/denner-s/gorpcstress/internal/config"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+)
+
+func TestPickMethod(t *testing.T) {
+	cfg := testConfig("localhost:1234")
+	cfg.MethodMix = []config.MethodSpec{{Method: "Users.Get", Weight: 1}, {Method: "Users.List", Weight: 2}, {Method: "Users.Update", Weight: 7}}
+	sr := NewStressRunner(cfg, newCollector())
+	if want := []int{1, 3, 10}; !reflect.DeepEqual(sr.cumWeights, want) {
+		t.Fatalf("pesos acumulados %v, esperado %v", sr.cumWeights, want)
+	}
+
+	// Cada método é sorteado em proporção ao seu peso
+	const draws = 100000
+	r := rand.New(rand.NewPCG(1, 2))
+	counts := make([]int, len(sr.methods))
+	for i := 0; i < draws; i++ {
+		counts[sr.pickMethod(r).index]++
+	}
+	for i, spec := range cfg.MethodMix {
+		want := draws * spec.Weight / 10
+		if diff := counts[i] - want; diff < -want/20 || diff > want/20 {
+			t.Errorf("%s sorteado %d vezes, esperado cerca de %d", spec.Method, counts[i], want)
+		}
+	}
+}
+
+func TestMethodMix(t *testing.T) {
+	ts := startServer(t, 0)
+	cfg := testConfig(ts.addr)
+	cfg.TotalRequests = 400
+	cfg.MethodMix = []config.MethodSpec{{Method: "Arithmetic.Multiply", Weight: 3}, {Method: "Arithmetic.Divide", Weight: 1}}
+	collector := newCollector()
+	NewStressRunner(cfg, collector).Run(context.Background())
+
+	// O servidor não tem Arithmetic.Divide: apenas as requisições desse método falham
+	m := collector.GetMetrics()
+	if len(m.Methods) != 2 {
+		t.Fatalf("%d métodos nas métricas, esperados 2", len(m.Methods))
+	}
+	multiply, divide := m.Methods[0], m.Methods[1]
+	if multiply.TotalRequests+divide.TotalRequests != 400 || multiply.Errors != 0 || divide.Errors != divide.TotalRequests {
+		t.Errorf("Multiply: %d requisições, %d erros; Divide: %d requisições, %d erros", multiply.TotalRequests, multiply.Errors, divide.TotalRequests, divide.Errors)
+	}
+	if multiply.TotalRequests < 240 || multiply.TotalRequests > 360 {
+		t.Errorf("Multiply recebeu %d das 400 requisições, esperado cerca de 300", multiply.TotalRequests)
+	}
+}
+
+func TestNextRequestIsReproducible(t *testing.T) {
+	// sequence devolve os métodos e valores sorteados para as primeiras requisições
+	sequence := func(seed int64) []string {
+		cfg := testConfig("localhost:1234")
+		cfg.Seed = seed
+		cfg.MethodMix = []config.MethodSpec{{Method: "Users.Get", Weight: 1}, {Method: "Users.List", Weight: 1}}
+		sr := NewStressRunner(cfg, newCollector())
+
+		var got []string
+		for i := 0; i < 20; i++ {
+			m, gen := sr.nextRequest(1)
+			got = append(got, fmt.Sprintf("%d:%s:%d", gen.Seq, m.name, gen.Rand.Int64()))
+		}
+		return got
+	}
+
+	first := sequence(7)
+	if again := sequence(7); !reflect.DeepEqual(first, again) {
+		t.Errorf("mesma semente, sequências diferentes:\n%v\n%v", first, again)
+	}
+	if other := sequence(8); reflect.DeepEqual(first, other) {
+		t.Error("sementes diferentes produziram a mesma sequência")
+	}
+}
@@ -0,0 +1,271 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"github.com/denner-s/gorpcstress/internal/metrics"
+	"github.com/denner-s/gorpcstress/pkg/rpcclient"
+	"log"
+	"net/rpc"
+	"sync"
+	"time"
+)
+
+// pipeline mantém até -pipeline chamadas em voo nas conexões de uma sessão, enviadas
+// de forma assíncrona com rpc.Client.Go. Uma chamada que excede o timeout é registrada
+// como erro no prazo, mas continua ocupando seu lugar até a resposta chegar ou a
+// conexão ser perdida; se todas as chamadas em voo excederem o timeout, a conexão é
+// considerada travada e substituída.
+type pipeline struct {
+	sr       *StressRunner
+	session  *connSession
+	results  chan<- metrics.Result
+	depth    int                          // Máximo de chamadas em voo
+	done     chan *rpc.Call               // Conclusões das chamadas enviadas
+	inflight map[*rpc.Call]*pipelinedCall // Chamadas em voo
+	timer    *time.Timer                  // Prazo da chamada em voo mais antiga
+	slots    chan struct{}                // Lugares em voo reservados pelo runner (malha aberta)
+}
+
+// pipelinedCall registra uma chamada em voo no pipeline
+type pipelinedCall struct {
+	scheduledCall
+	method   *method     // Método sorteado para a chamada
+	start    time.Time   // Horário do envio efetivo
+	late     bool        // Envio posterior ao horário agendado
+	release  func(error) // Libera a conexão da chamada
+	timedOut bool        // Já registrada como timeout
+}
+
+// newPipeline cria o pipeline de uma sessão
+func (sr *StressRunner) newPipeline(session *connSession, results chan<- metrics.Result) *pipeline {
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	return &pipeline{
+		sr:       sr,
+		session:  session,
+		results:  results,
+		depth:    sr.cfg.Pipeline,
+		done:     make(chan *rpc.Call, sr.cfg.Pipeline), // Nunca há mais conclusões que chamadas em voo
+		inflight: make(map[*rpc.Call]*pipelinedCall, sr.cfg.Pipeline),
+		timer:    timer,
+	}
+}
+
+// free libera o lugar reservado pelo runner para um envio agendado
+func (p *pipeline) free() {
+	if p.slots != nil {
+		<-p.slots
+	}
+}
+
+// full indica se o pipeline atingiu o máximo de chamadas em voo
+func (p *pipeline) full() bool {
+	return len(p.inflight) >= p.depth
+}
+
+// empty indica se não há chamadas em voo
+func (p *pipeline) empty() bool {
+	return len(p.inflight) == 0
+}
+
+// send envia uma chamada assíncrona pela conexão da sessão
+func (p *pipeline) send(ctx context.Context, call scheduledCall, late bool) error {
+	caller, release, err := p.session.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	client, ok := caller.(*rpcclient.Client)
+	if !ok {
+		release(nil)
+		return fmt.Errorf("pipelining exige o transporte net/rpc")
+	}
+
+	m, gen := p.sr.nextRequest(p.session.worker)
+	args := m.payload.NewArgs(gen)
+	pc := &pipelinedCall{scheduledCall: call, method: m, start: time.Now(), late: late, release: release}
+	p.inflight[client.Go(m.name, args, m.payload.NewReply(), p.done)] = pc
+	return nil
+}
+
+// wait aguarda o próximo evento (uma conclusão ou um timeout) e registra seu resultado.
+// Retorna falso se as chamadas em voo foram abandonadas ao fim do prazo de interrupção.
+func (p *pipeline) wait() bool {
+	select {
+	case call := <-p.done:
+		p.complete(call)
+	case <-p.deadline():
+		p.expire()
+	case <-p.sr.calls.Done():
+		p.abandon()
+		return false
+	}
+	return true
+}
+
+// drain aguarda a conclusão de todas as chamadas em voo
+func (p *pipeline) drain() {
+	for !p.empty() && p.wait() {
+	}
+}
+
+// complete registra a conclusão de uma chamada; chamadas já registradas como timeout
+// apenas liberam seu lugar
+func (p *pipeline) complete(call *rpc.Call) {
+	pc, ok := p.inflight[call]
+	if !ok {
+		return
+	}
+	delete(p.inflight, call)
+	p.free()
+	pc.release(call.Error)
+	if pc.timedOut {
+		return
+	}
+
+	result := newResult(pc.method, pc.start, pc.intended, time.Now(), call.Error, call.Reply)
+	result.Late, result.Stage = pc.late, pc.stage
+	p.results <- result
+}
+
+// deadline devolve o canal que dispara no prazo da chamada em voo mais antiga ainda não
+// registrada como timeout, ou nil se não houver prazo a aguardar
+func (p *pipeline) deadline() <-chan time.Time {
+	if p.sr.cfg.Timeout <= 0 {
+		return nil
+	}
+
+	var oldest time.Time
+	for _, pc := range p.inflight {
+		if !pc.timedOut && (oldest.IsZero() || pc.start.Before(oldest)) {
+			oldest = pc.start
+		}
+	}
+	if oldest.IsZero() {
+		return nil
+	}
+	// Um disparo anterior não consumido anteciparia o novo prazo
+	if !p.timer.Stop() {
+		select {
+		case <-p.timer.C:
+		default:
+		}
+	}
+	p.timer.Reset(time.Until(oldest.Add(p.sr.cfg.Timeout)))
+	return p.timer.C
+}
+
+// expire registra como timeout as chamadas cujo prazo venceu. Se nenhuma chamada em
+// voo estiver dentro do prazo, a conexão é descartada e suas chamadas são concluídas
+// com rpc.ErrShutdown.
+func (p *pipeline) expire() {
+	now := time.Now()
+	stuck := true
+	for _, pc := range p.inflight {
+		if pc.timedOut {
+			continue
+		}
+		if now.Sub(pc.start) < p.sr.cfg.Timeout {
+			stuck = false
+			continue
+		}
+		pc.timedOut = true
+		p.results <- metrics.Result{
+			Duration:     now.Sub(pc.start),
+			ResponseTime: now.Sub(pc.intended),
+			Error:        fmt.Errorf("%w após %v", rpcclient.ErrTimeout, p.sr.cfg.Timeout),
+			Category:     metrics.ErrorTimeout,
+			TimedOut:     true,
+			Late:         pc.late,
+			Stage:        pc.stage,
+			Method:       pc.method.index,
+		}
+	}
+
+	if stuck {
+		for _, pc := range p.inflight {
+			pc.release(rpc.ErrShutdown)
+		}
+	}
+}
+
+// abandon registra como abandonadas as chamadas em voo ao fim do prazo de interrupção
+func (p *pipeline) abandon() {
+	for call, pc := range p.inflight {
+		if !pc.timedOut {
+			p.results <- metrics.Result{Abandoned: true}
+		}
+		delete(p.inflight, call)
+		p.free()
+	}
+}
+
+// runPipelinedWorker executa um lote de requisições mantendo até -pipeline chamadas em
+// voo. Falhas de conexão encerram o lote como em runWorker.
+func (sr *StressRunner) runPipelinedWorker(ctx context.Context, session *connSession, requests int, results chan<- metrics.Result) {
+	p := sr.newPipeline(session, results)
+	defer p.drain()
+
+	for sent := 0; sent < requests; {
+		for !p.full() && sent < requests && ctx.Err() == nil {
+			if err := p.send(ctx, scheduledCall{intended: time.Now()}, false); err != nil {
+				if ctx.Err() == nil {
+					log.Printf("Falha na conexão RPC: %v", err)
+					sr.sendConnectionErrors(requests-sent, results, err)
+				}
+				return
+			}
+			sent++
+		}
+		if ctx.Err() != nil || !p.wait() {
+			return
+		}
+	}
+}
+
+// runPipelinedScheduledWorker executa as chamadas agendadas dos modos de malha aberta,
+// aceitando novos envios enquanto houver menos de -pipeline chamadas em voo
+func (sr *StressRunner) runPipelinedScheduledWorker(ctx context.Context, schedule <-chan scheduledCall, ready *sync.WaitGroup, results chan<- metrics.Result) {
+	session := sr.newSession(results)
+	defer session.close()
+
+	err := session.warmup(ctx)
+	ready.Done()
+	if err != nil {
+		log.Printf("Falha na conexão RPC: %v", err)
+		for call := range schedule {
+			<-sr.slots
+			results <- connectionFailure(err, call.stage, sr.nextMethod())
+		}
+		return
+	}
+
+	p := sr.newPipeline(session, results)
+	p.slots = sr.slots
+	for schedule != nil || !p.empty() {
+		// Com o pipeline cheio o worker não aceita envios, que são descartados pelo runner
+		next := schedule
+		if p.full() {
+			next = nil
+		}
+
+		select {
+		case call, ok := <-next:
+			if !ok {
+				schedule = nil
+				continue
+			}
+			if err := p.send(ctx, call, isLate(call)); err != nil {
+				p.free()
+				results <- connectionFailure(err, call.stage, p.sr.nextMethod())
+			}
+		case call := <-p.done:
+			p.complete(call)
+		case <-p.deadline():
+			p.expire()
+		case <-sr.calls.Done():
+			p.abandon()
+			return
+		}
+	}
+}
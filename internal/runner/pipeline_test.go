@@ -0,0 +1,91 @@
+package runner
+
+import (
+	"context"
+	"github.com/denner-s/gorpcstress/internal/metrics"
+	"testing"
+	"time"
+)
+
+func TestPipelinedRun(t *testing.T) {
+	ts := startServer(t, 20*time.Millisecond)
+	cfg := testConfig(ts.addr)
+	cfg.Concurrency, cfg.Pipeline = 1, 4
+	collector := newCollector()
+	NewStressRunner(cfg, collector).Run(context.Background())
+
+	// Com 4 chamadas em voo, as 20 chamadas de 20ms levam cerca de 100ms em uma conexão
+	m := collector.GetMetrics()
+	if m.TotalRequests != 20 || m.Errors != 0 {
+		t.Errorf("%d requisições e %d erros; esperadas 20 sem erros", m.TotalRequests, m.Errors)
+	}
+	if elapsed := m.EndTime.Sub(m.StartTime); elapsed >= 300*time.Millisecond {
+		t.Errorf("teste durou %v; as chamadas não foram enviadas em pipeline", elapsed)
+	}
+	if got := ts.conns.Load(); got != 1 {
+		t.Errorf("servidor recebeu %d conexões, esperada 1", got)
+	}
+}
+
+func TestPipelineTimeouts(t *testing.T) {
+	ts := startServer(t, time.Second)
+	cfg := testConfig(ts.addr)
+	cfg.Timeout, cfg.Pipeline = 100*time.Millisecond, 4
+	sr := NewStressRunner(cfg, newCollector())
+	sr.calls = context.Background()
+
+	results := make(chan metrics.Result, 10)
+	session := sr.newSession(results)
+	defer session.close()
+	p := sr.newPipeline(session, results)
+
+	// Duas chamadas enviadas com 50ms de diferença vencem o prazo uma de cada vez
+	for i := 0; i < 2; i++ {
+		if i > 0 {
+			time.Sleep(50 * time.Millisecond)
+		}
+		if err := p.send(context.Background(), scheduledCall{intended: time.Now()}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	timeouts := func() (n int) {
+		for {
+			select {
+			case r := <-results:
+				if r.Conn == nil && r.Error != nil {
+					n++
+				}
+			default:
+				return n
+			}
+		}
+	}
+
+	// A primeira chamada vence o prazo, mas a segunda ainda está no prazo: a conexão é mantida
+	p.wait()
+	if n := timeouts(); n != 1 {
+		t.Fatalf("%d timeouts após o primeiro prazo, esperado 1", n)
+	}
+	if session.client == nil {
+		t.Fatal("conexão descartada com uma chamada ainda no prazo")
+	}
+
+	// Com as duas chamadas vencidas, a conexão é considerada travada e descartada
+	p.wait()
+	if n := timeouts(); n != 1 {
+		t.Fatalf("%d timeouts após o segundo prazo, esperado 1", n)
+	}
+	if session.client != nil {
+		t.Fatal("conexão mantida com todas as chamadas vencidas")
+	}
+
+	// As chamadas da conexão descartada liberam seus lugares sem novos resultados
+	p.drain()
+	if !p.empty() {
+		t.Errorf("%d chamadas ainda em voo", len(p.inflight))
+	}
+	if n := timeouts(); n != 0 {
+		t.Errorf("%d resultados após o descarte da conexão, esperado nenhum", n)
+	}
+}
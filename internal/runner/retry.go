@@ -0,0 +1,40 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"github.com/denner-s/gorpcstress/internal/metrics"
+	"math/rand/v2"
+	"time"
+)
+
+// errReconnectDisabled é devolvido ao obter a conexão de uma sessão cuja conexão foi
+// perdida quando a reconexão está desativada
+var errReconnectDisabled = errors.New("conexão perdida e reconexão desativada (-reconnect=false)")
+
+// backoff calcula a espera antes da nova tentativa de número attempt (a partir de zero):
+// a espera base dobra a cada tentativa até o máximo, e o jitter sorteia um valor entre
+// zero e esse limite para que os workers não repitam as tentativas em sincronia
+func (sr *StressRunner) backoff(attempt int) time.Duration {
+	limit := sr.cfg.RetryMaxBackoff
+	if attempt < 63 && sr.cfg.RetryBackoff <= limit>>attempt {
+		limit = sr.cfg.RetryBackoff << attempt
+	}
+	return rand.N(limit) + 1
+}
+
+// sleepBackoff aguarda o backoff da nova tentativa; retorna falso se ctx for cancelado antes
+func (sr *StressRunner) sleepBackoff(ctx context.Context, attempt int) bool {
+	return sleepUntil(ctx, time.Now().Add(sr.backoff(attempt)))
+}
+
+// retryable indica se uma chamada que falhou pode ser repetida: apenas falhas de
+// conexão, em que a chamada pode não ter chegado ao servidor. Timeouts não são
+// repetidos, já que a chamada continua em voo no servidor.
+func retryable(category metrics.ErrorCategory) bool {
+	switch category {
+	case metrics.ErrorDial, metrics.ErrorReset, metrics.ErrorShutdown:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"github.com/denner-s/gorpcstress/internal/config"
+	"github.com/denner-s/gorpcstress/internal/metrics"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	sr := &StressRunner{cfg: &config.Config{RetryBackoff: 100 * time.Millisecond, RetryMaxBackoff: time.Second}}
+	tests := []struct {
+		attempt int
+		limit   time.Duration
+	}{
+		{0, 100 * time.Millisecond},
+		{1, 200 * time.Millisecond},
+		{2, 400 * time.Millisecond},
+		{3, 800 * time.Millisecond},
+		{4, time.Second},
+		{10, time.Second},
+		{62, time.Second},
+		{63, time.Second}, // Deslocamentos que estourariam o int64 usam o máximo
+		{1000, time.Second},
+	}
+	for _, tt := range tests {
+		// O jitter sorteia a espera entre zero (exclusive) e o limite da tentativa
+		longest := time.Duration(0)
+		for i := 0; i < 1000; i++ {
+			d := sr.backoff(tt.attempt)
+			if d <= 0 || d > tt.limit {
+				t.Fatalf("backoff(%d) = %v, fora de (0, %v]", tt.attempt, d, tt.limit)
+			}
+			longest = max(longest, d)
+		}
+		if longest < tt.limit/2 {
+			t.Errorf("backoff(%d): maior espera %v em 1000 sorteios até %v", tt.attempt, longest, tt.limit)
+		}
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		category metrics.ErrorCategory
+		want     bool
+	}{
+		{metrics.ErrorDial, true},
+		{metrics.ErrorReset, true},
+		{metrics.ErrorShutdown, true},
+		{metrics.ErrorTimeout, false},
+		{metrics.ErrorServer, false},
+		{metrics.ErrorValidation, false},
+		{metrics.ErrorUnknown, false},
+	}
+	for _, tt := range tests {
+		if got := retryable(tt.category); got != tt.want {
+			t.Errorf("retryable(%v) = %v, esperado %v", tt.category, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package runner
+
+import (
+	"context"
+	"github.com/denner-s/gorpcstress/internal/config"
+	"github.com/denner-s/gorpcstress/internal/metrics"
+	"log"
+	"math"
+	"sync"
+	"time"
+)
+
+// stagePollInterval define a frequência com que workers ociosos reavaliam o alvo de concorrência
+const stagePollInterval = 10 * time.Millisecond
+
+// prepareStages registra os estágios no coletor e, quando os estágios controlam a
+// taxa, a taxa alvo média do perfil
+func (sr *StressRunner) prepareStages() {
+	names := make([]string, len(sr.cfg.Stages))
+	var sends float64
+	var total time.Duration
+
+	from := 0.0
+	for i, stage := range sr.cfg.Stages {
+		names[i] = stage.Label(from)
+		sends += (from + stage.Target) / 2 * stage.Duration.Seconds()
+		total += stage.Duration
+		from = stage.Target
+	}
+	sr.metrics.SetStages(names)
+
+	if sr.cfg.StageTarget == config.StageTargetRate {
+		sr.metrics.SetTargetRate(sends / total.Seconds())
+	} else {
+		sr.metrics.SetTargetRate(0)
+	}
+}
+
+// runStagesMode executa o perfil de carga, controlando a taxa ou a concorrência
+func (sr *StressRunner) runStagesMode(ctx context.Context, wg *sync.WaitGroup, results chan<- metrics.Result) {
+	if sr.cfg.StageTarget == config.StageTargetConcurrency {
+		sr.runStagedConcurrencyMode(ctx, wg, results)
+		return
+	}
+	sr.runStagedRateMode(ctx, wg, results)
+}
+
+// runStagedRateMode executa o perfil em malha aberta, com a taxa de envio variando
+// conforme os estágios e -concurrency limitando as chamadas em voo
+func (sr *StressRunner) runStagedRateMode(ctx context.Context, wg *sync.WaitGroup, results chan<- metrics.Result) {
+	schedule := sr.startScheduledWorkers(ctx, wg, results)
+	start := time.Now()
+
+	profile := &rateProfile{stages: sr.cfg.Stages}
+	offset, stage, ok := profile.next()
+	for ok {
+		nextOffset, nextStage, nextOK := profile.next()
+
+		// Um envio é considerado atrasado se sair depois do horário do envio seguinte
+		var lateAfter time.Duration
+		if nextOK {
+			lateAfter = nextOffset - offset
+		}
+
+		intended := start.Add(offset)
+		if !sleepUntil(ctx, intended) {
+			break // Teste interrompido
+		}
+		sr.dispatch(schedule, scheduledCall{intended: intended, lateAfter: lateAfter, stage: stage}, results)
+
+		offset, stage, ok = nextOffset, nextStage, nextOK
+	}
+	close(schedule)
+}
+
+// runStagedConcurrencyMode executa o perfil em malha fechada, ativando e desativando
+// workers conforme o número alvo de cada estágio
+func (sr *StressRunner) runStagedConcurrencyMode(ctx context.Context, wg *sync.WaitGroup, results chan<- metrics.Result) {
+	var peak float64
+	for _, stage := range sr.cfg.Stages {
+		peak = math.Max(peak, stage.Target)
+	}
+
+	start := time.Now()
+	for id := 0; id < int(math.Ceil(peak)); id++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			sr.runStagedWorker(ctx, id, start, results)
+		}(id)
+	}
+}
+
+// runStagedWorker executa chamadas em sequência enquanto seu índice estiver dentro
+// do alvo de concorrência do estágio corrente. A conexão é aberta na primeira ativação.
+func (sr *StressRunner) runStagedWorker(ctx context.Context, id int, start time.Time, results chan<- metrics.Result) {
+	session := sr.newSession(results)
+	defer session.close()
+
+	for ctx.Err() == nil {
+		stage, target, ok := stageAt(sr.cfg.Stages, time.Since(start))
+		if !ok {
+			return // Fim do perfil de carga
+		}
+
+		// Worker fora do alvo atual aguarda uma nova avaliação
+		if float64(id) >= target {
+			time.Sleep(stagePollInterval)
+			continue
+		}
+
+		result, err := sr.execute(ctx, session, time.Now())
+		if err != nil && ctx.Err() != nil {
+			return // Conexão cancelada pela interrupção do teste
+		}
+		result.Stage = stage
+		results <- result
+		if err != nil {
+			log.Printf("Falha na conexão RPC: %v", err)
+			time.Sleep(stagePollInterval)
+		}
+	}
+}
+
+// stageAt retorna o estágio corrente e o alvo interpolado linearmente no instante
+// elapsed; ok é falso quando o perfil já terminou
+func stageAt(stages []config.Stage, elapsed time.Duration) (index int, target float64, ok bool) {
+	from := 0.0
+	for i, stage := range stages {
+		if elapsed < stage.Duration {
+			progress := float64(elapsed) / float64(stage.Duration)
+			return i, from + (stage.Target-from)*progress, true
+		}
+		elapsed -= stage.Duration
+		from = stage.Target
+	}
+	return 0, 0, false
+}
+
+// rateProfile calcula os horários de envio de um perfil de taxa em estágios. Como a
+// taxa varia linearmente dentro de cada estágio, o número acumulado de envios é uma
+// função quadrática do tempo e o horário de cada envio é obtido de forma exata.
+type rateProfile struct {
+	stages []config.Stage
+	stage  int           // Estágio corrente
+	offset time.Duration // Início do estágio corrente em relação ao início do perfil
+	base   float64       // Envios acumulados até o início do estágio corrente
+	sent   int           // Envios já agendados
+}
+
+// next retorna o deslocamento e o estágio do próximo envio; ok é falso ao fim do perfil
+func (p *rateProfile) next() (offset time.Duration, stage int, ok bool) {
+	p.sent++
+	for p.stage < len(p.stages) {
+		current := p.stages[p.stage]
+		from := 0.0
+		if p.stage > 0 {
+			from = p.stages[p.stage-1].Target
+		}
+
+		seconds := current.Duration.Seconds()
+		total := (from + current.Target) / 2 * seconds // Envios previstos no estágio
+		n := float64(p.sent) - p.base
+
+		if n <= total && seconds > 0 {
+			// Resolve from*t + a*t² = n na forma numericamente estável
+			a := (current.Target - from) / (2 * seconds)
+			t := 2 * n / (from + math.Sqrt(math.Max(from*from+4*a*n, 0)))
+			return p.offset + time.Duration(t*float64(time.Second)), p.stage, true
+		}
+
+		p.base += total
+		p.offset += current.Duration
+		p.stage++
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,100 @@
+package runner
+
+import (
+	"github.com/denner-s/gorpcstress/internal/config"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestStageAt(t *testing.T) {
+	// Rampa até 100 em 10s, platô de 10s, salto para 50 e descida até zero em 5s
+	stages := []config.Stage{
+		{Duration: 10 * time.Second, Target: 100},
+		{Duration: 10 * time.Second, Target: 100},
+		{Duration: 0, Target: 50},
+		{Duration: 5 * time.Second, Target: 0},
+	}
+	tests := []struct {
+		elapsed time.Duration
+		index   int
+		target  float64
+		ok      bool
+	}{
+		{0, 0, 0, true},
+		{2500 * time.Millisecond, 0, 25, true},
+		{10 * time.Second, 1, 100, true},
+		{19 * time.Second, 1, 100, true},
+		{20 * time.Second, 3, 50, true},
+		{22500 * time.Millisecond, 3, 25, true},
+		{25 * time.Second, 0, 0, false},
+		{time.Hour, 0, 0, false},
+	}
+	for _, tt := range tests {
+		index, target, ok := stageAt(stages, tt.elapsed)
+		if index != tt.index || math.Abs(target-tt.target) > 1e-9 || ok != tt.ok {
+			t.Errorf("stageAt(%v) = %d, %g, %v; esperado %d, %g, %v", tt.elapsed, index, target, ok, tt.index, tt.target, tt.ok)
+		}
+	}
+}
+
+func TestRateProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		stages  []config.Stage
+		counts  []int                 // Envios esperados por estágio
+		offsets map[int]time.Duration // Deslocamento esperado de alguns envios, a partir de 1
+	}{
+		{
+			name:    "platô",
+			stages:  []config.Stage{{Duration: 0, Target: 100}, {Duration: 2 * time.Second, Target: 100}},
+			counts:  []int{0, 200},
+			offsets: map[int]time.Duration{1: 10 * time.Millisecond, 100: time.Second, 200: 2 * time.Second},
+		},
+		{
+			// Na rampa de 0 a 100 req/s em 10s, n envios acumulam até t = sqrt(n/5)
+			name:    "rampa e platô",
+			stages:  []config.Stage{{Duration: 10 * time.Second, Target: 100}, {Duration: 10 * time.Second, Target: 100}},
+			counts:  []int{500, 1000},
+			offsets: map[int]time.Duration{5: time.Second, 125: 5 * time.Second, 500: 10 * time.Second, 1500: 20 * time.Second},
+		},
+		{
+			name:    "descida",
+			stages:  []config.Stage{{Duration: 0, Target: 10}, {Duration: 4 * time.Second, Target: 0}},
+			counts:  []int{0, 20},
+			offsets: map[int]time.Duration{20: 4 * time.Second},
+		},
+		{
+			name:   "sem envios",
+			stages: []config.Stage{{Duration: 5 * time.Second, Target: 0}},
+			counts: []int{0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := &rateProfile{stages: tt.stages}
+			counts := make([]int, len(tt.stages))
+			previous := time.Duration(0)
+			for n := 1; ; n++ {
+				offset, stage, ok := profile.next()
+				if !ok {
+					break
+				}
+				if offset < previous {
+					t.Fatalf("envio %d em %v, antes do anterior (%v)", n, offset, previous)
+				}
+				previous = offset
+				counts[stage]++
+
+				if want, found := tt.offsets[n]; found && (offset-want).Abs() > time.Microsecond {
+					t.Errorf("envio %d em %v, esperado %v", n, offset, want)
+				}
+			}
+			for i := range counts {
+				if counts[i] != tt.counts[i] {
+					t.Errorf("estágio %d: %d envios, esperados %d", i, counts[i], tt.counts[i])
+				}
+			}
+		})
+	}
+}
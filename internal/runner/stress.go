@@ -1,46 +1,89 @@
 package runner
 
 import (
-	"encoding/json"
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"github.com/denner-s/gorpcstress/internal/config"
 	"github.com/denner-s/gorpcstress/internal/metrics"
 	"github.com/denner-s/gorpcstress/pkg/rpcclient"
+	"github.com/denner-s/gorpcstress/pkg/validate"
+	"io"
 	"log"
 	"net"
-	"os"
+	"net/rpc"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // StressRunner gerencia toda a execução do teste de carga RPC
 type StressRunner struct {
-	cfg         *config.Config     // Configurações do teste
-	metrics     *metrics.Collector // Coletor de métricas de desempenho
-	payloadData *rpcclient.Args    // Dados do payload para as chamadas RPC
+	cfg        *config.Config     // Configurações do teste
+	metrics    *metrics.Collector // Coletor de métricas de desempenho
+	methods    []*method          // Métodos da mistura de tráfego, com payloads e validadores
+	cumWeights []int              // Pesos acumulados dos métodos, para o sorteio de cada requisição
+	tls        *tls.Config        // Configuração TLS das conexões (nil sem TLS)
+	pool       *connPool          // Conexões compartilhadas (modo pool)
+	idle       chan *connSession  // Sessões ociosas reaproveitadas entre as goroutines do modo de duração
+	slots      chan struct{}      // Lugares em voo dos modos de malha aberta com pipelining (nil sem pipelining)
+	calls      context.Context    // Contexto das chamadas em voo, cancelado ao fim do prazo de interrupção
+	requests   atomic.Int64       // Requisições numeradas, para os geradores dos payloads
+	workers    atomic.Int64       // Sessões criadas, que numeram os workers
 }
 
-// Run inicia e controla o fluxo principal do teste de carga
-func (sr *StressRunner) Run() {
+// Run inicia e controla o fluxo principal do teste de carga. O cancelamento de ctx
+// interrompe o envio de novas chamadas; as chamadas em voo têm até -grace para
+// terminar e então são abandonadas, e as métricas são marcadas como parciais.
+func (sr *StressRunner) Run(ctx context.Context) {
 	var wg sync.WaitGroup
 	results := make(chan metrics.Result, sr.cfg.Concurrency*2) // Canal bufferizado para resultados
 	done := make(chan struct{})                                // Canal para sinalização de término
 
+	sr.metrics.SetTargetRate(sr.cfg.Rate)
+	if len(sr.cfg.Stages) > 0 {
+		sr.prepareStages()
+	}
+	sr.metrics.Start()
+
+	// Conexões compartilhadas entre os workers e sessões reaproveitadas
+	if sr.cfg.ConnMode == config.ConnModePool {
+		sr.pool = newConnPool(sr.cfg.PoolSize)
+	}
+	sr.idle = make(chan *connSession, sr.cfg.Concurrency)
+
+	// As chamadas em voo sobrevivem à interrupção até o fim do prazo
+	calls, abandon := context.WithCancel(context.WithoutCancel(ctx))
+	defer abandon()
+	sr.calls = calls
+	stopGrace := context.AfterFunc(ctx, func() { time.AfterFunc(sr.cfg.GracePeriod, abandon) })
+	defer stopGrace()
+
 	// Goroutine para coletar resultados de forma assíncrona
 	go sr.collectResults(results, done)
 
 	// Seleciona o modo de operação baseado na configuração
-	if sr.cfg.Duration > 0 {
-		sr.runDurationMode(time.Now(), &wg, results) // Modo de execução contínua por tempo
+	if len(sr.cfg.Stages) > 0 {
+		sr.runStagesMode(ctx, &wg, results) // Modo de perfil de carga em estágios
+	} else if sr.cfg.Rate > 0 {
+		sr.runRateMode(ctx, &wg, results) // Modo de malha aberta com taxa constante
+	} else if sr.cfg.Duration > 0 {
+		sr.runDurationMode(ctx, time.Now(), &wg, results) // Modo de execução contínua por tempo
 	} else {
-		sr.runRequestMode(&wg, results) // Modo de número fixo de requisições
+		sr.runRequestMode(ctx, &wg, results) // Modo de número fixo de requisições
 	}
 
 	// Espera a conclusão de todas as goroutines
 	wg.Wait()
+	sr.closeConnections()
 	close(results) // Fecha o canal de resultados
 	<-done         // Aguarda a finalização do processamento
+	sr.metrics.Stop()
+
+	if ctx.Err() != nil {
+		sr.metrics.SetInterrupted()
+	}
 }
 
 // NewStressRunner é o construtor que inicializa o testador de carga
@@ -50,53 +93,181 @@ func NewStressRunner(cfg *config.Config, collector *metrics.Collector) *StressRu
 		metrics: collector,
 	}
 
-	// Carrega payload personalizado ou usa valores padrão
-	if cfg.PayloadFile != "" {
-		runner.loadPayload()
-	} else {
-		// Valores padrão que correspondem ao exemplo do servidor
-		runner.payloadData = &rpcclient.Args{A: 5, B: 3}
-	}
+	// Carrega os payloads personalizados ou padrão e os validadores de cada método
+	runner.loadMethods()
 
-	return runner
-}
-
-// loadPayload carrega dados de chamada de um arquivo JSON
-func (sr *StressRunner) loadPayload() {
-	file, err := os.Open(sr.cfg.PayloadFile)
+	tlsConfig, err := cfg.TLSConfig()
 	if err != nil {
-		log.Fatalf("Falha ao abrir arquivo de payload: %v", err)
+		log.Fatalf("Configuração TLS inválida: %v", err)
 	}
-	defer func(file *os.File) {
-		if err := file.Close(); err != nil {
-			log.Printf("Erro ao fechar arquivo: %v", err)
-		}
-	}(file)
+	runner.tls = tlsConfig
 
-	sr.payloadData = &rpcclient.Args{}
-	if err := json.NewDecoder(file).Decode(sr.payloadData); err != nil {
-		log.Fatalf("Erro na decodificação do JSON: %v", err)
-	}
+	return runner
 }
 
 // runDurationMode executa o teste continuamente por um período específico
-func (sr *StressRunner) runDurationMode(start time.Time, wg *sync.WaitGroup, results chan<- metrics.Result) {
+func (sr *StressRunner) runDurationMode(ctx context.Context, start time.Time, wg *sync.WaitGroup, results chan<- metrics.Result) {
 	ticker := time.NewTicker(time.Second / time.Duration(sr.cfg.Concurrency))
 	defer ticker.Stop()
 
 	// Loop enquanto estiver dentro da duração configurada
 	for time.Since(start) < sr.cfg.Duration {
-		<-ticker.C // Controla a taxa de requisições
+		var intended time.Time
+		select {
+		case intended = <-ticker.C: // Controla a taxa de requisições
+		case <-ctx.Done():
+			return
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Executa 1 requisição por goroutine, reaproveitando a conexão de uma sessão ociosa
+			session := sr.idleSession(results)
+			sr.runWorker(ctx, session, 1, intended, results)
+			sr.releaseSession(session)
+		}()
+	}
+}
+
+// scheduledCall representa um envio agendado pelos modos de malha aberta
+type scheduledCall struct {
+	intended  time.Time     // Horário agendado para o envio
+	lateAfter time.Duration // Atraso a partir do qual o envio é considerado atrasado
+	stage     int           // Estágio do perfil de carga do envio
+}
+
+// runRateMode executa o teste em malha aberta: as chamadas seguem um cronograma fixo
+// derivado de -rate, independente da velocidade do servidor, e -concurrency limita
+// quantas chamadas podem estar em voo ao mesmo tempo.
+func (sr *StressRunner) runRateMode(ctx context.Context, wg *sync.WaitGroup, results chan<- metrics.Result) {
+	interval := time.Duration(float64(time.Second) / sr.cfg.Rate)
+	schedule := sr.startScheduledWorkers(ctx, wg, results)
+	start := time.Now()
+
+	for i := 0; ; i++ {
+		intended := start.Add(time.Duration(i) * interval)
+
+		// Encerra ao fim da duração ou após agendar o total de requisições
+		if sr.cfg.Duration > 0 {
+			if intended.Sub(start) >= sr.cfg.Duration {
+				break
+			}
+		} else if i >= sr.cfg.TotalRequests {
+			break
+		}
+
+		if !sleepUntil(ctx, intended) {
+			break // Teste interrompido
+		}
+		sr.dispatch(schedule, scheduledCall{intended: intended, lateAfter: interval}, results)
+	}
+	close(schedule)
+}
+
+// startScheduledWorkers inicia os workers persistentes dos modos de malha aberta e
+// aguarda que todos estabeleçam suas conexões antes de liberar o cronograma
+func (sr *StressRunner) startScheduledWorkers(ctx context.Context, wg *sync.WaitGroup, results chan<- metrics.Result) chan scheduledCall {
+	schedule := make(chan scheduledCall) // Sem buffer: só entrega o envio se houver worker livre
+
+	// Com pipelining a disponibilidade é controlada pelos lugares em voo de todos os
+	// workers; o buffer acomoda os envios aceitos até que um worker com lugar os receba
+	if sr.cfg.Pipeline > 1 {
+		sr.slots = make(chan struct{}, sr.cfg.Concurrency*sr.cfg.Pipeline)
+		schedule = make(chan scheduledCall, cap(sr.slots))
+	}
+
+	var ready sync.WaitGroup
+	ready.Add(sr.cfg.Concurrency)
+	for i := 0; i < sr.cfg.Concurrency; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			sr.runWorker(1, results) // Executa 1 requisição por goroutine
+			if sr.cfg.Pipeline > 1 {
+				sr.runPipelinedScheduledWorker(ctx, schedule, &ready, results)
+				return
+			}
+			sr.runScheduledWorker(ctx, schedule, &ready, results)
 		}()
 	}
+
+	ready.Wait()
+	return schedule
+}
+
+// sleepUntil aguarda até o horário informado; retorna falso se ctx for cancelado antes
+func sleepUntil(ctx context.Context, t time.Time) bool {
+	wait := time.Until(t)
+	if wait <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// dispatch entrega um envio agendado a um worker livre. Em malha aberta o runner nunca
+// espera por um worker: se todos estiverem ocupados o envio é descartado.
+func (sr *StressRunner) dispatch(schedule chan<- scheduledCall, call scheduledCall, results chan<- metrics.Result) {
+	if sr.slots != nil {
+		select {
+		case sr.slots <- struct{}{}:
+			schedule <- call // Há um lugar reservado, portanto o buffer nunca está cheio
+		default:
+			results <- metrics.Result{Dropped: true, Stage: call.stage}
+		}
+		return
+	}
+
+	select {
+	case schedule <- call:
+	default:
+		results <- metrics.Result{Dropped: true, Stage: call.stage}
+	}
+}
+
+// runScheduledWorker executa as chamadas agendadas com as conexões de sua sessão
+func (sr *StressRunner) runScheduledWorker(ctx context.Context, schedule <-chan scheduledCall, ready *sync.WaitGroup, results chan<- metrics.Result) {
+	session := sr.newSession(results)
+	defer session.close()
+
+	err := session.warmup(ctx)
+	ready.Done()
+	if err != nil {
+		log.Printf("Falha na conexão RPC: %v", err)
+		for call := range schedule {
+			results <- connectionFailure(err, call.stage, sr.nextMethod())
+		}
+		return
+	}
+
+	for call := range schedule {
+		late := isLate(call)
+		result, _ := sr.execute(ctx, session, call.intended)
+		result.Late = late
+		result.Stage = call.stage
+		results <- result
+	}
+}
+
+// isLate indica se o envio agendado está saindo com atraso em relação ao cronograma
+func isLate(call scheduledCall) bool {
+	// Tolerância mínima para não marcar como atraso a imprecisão natural do timer
+	lateAfter := call.lateAfter
+	if lateAfter < time.Millisecond {
+		lateAfter = time.Millisecond
+	}
+	return time.Since(call.intended) > lateAfter
 }
 
 // runRequestMode distribui requisições fixas entre workers
-func (sr *StressRunner) runRequestMode(wg *sync.WaitGroup, results chan<- metrics.Result) {
+func (sr *StressRunner) runRequestMode(ctx context.Context, wg *sync.WaitGroup, results chan<- metrics.Result) {
 	// Distribui requisições igualmente entre workers
 	base, remaining := distributeRequests(sr.cfg.Concurrency, sr.cfg.TotalRequests)
 
@@ -109,11 +280,69 @@ func (sr *StressRunner) runRequestMode(wg *sync.WaitGroup, results chan<- metric
 		wg.Add(1)
 		go func(count int) {
 			defer wg.Done()
-			sr.runWorker(count, results) // Executa lote de requisições
+			session := sr.newSession(results)
+			defer session.close()
+			if sr.cfg.Pipeline > 1 {
+				sr.runPipelinedWorker(ctx, session, count, results) // Lote com várias chamadas em voo
+				return
+			}
+			sr.runWorker(ctx, session, count, time.Now(), results) // Executa lote de requisições
 		}(reqCount)
 	}
 }
 
+// dial abre uma conexão com o servidor conforme a configuração do teste e registra
+// os tempos de conexão e dos handshakes TLS e HTTP; reconnect indica que a conexão
+// substitui uma conexão perdida. Falhas são repetidas até -dial-retries vezes, com
+// backoff
+func (sr *StressRunner) dial(ctx context.Context, results chan<- metrics.Result, reconnect bool) (rpcclient.Caller, error) {
+	opts := rpcclient.Options{
+		Timeout:   sr.cfg.Timeout,
+		Codec:     sr.cfg.Codec,
+		Headers:   sr.cfg.Headers,
+		BatchSize: sr.cfg.BatchSize,
+		TLS:       sr.tls,
+	}
+	if sr.cfg.HTTPConnect {
+		opts.HTTPPath = sr.cfg.RPCPath
+	}
+	// No modo pool cada cliente atende vários workers; o cliente HTTP mantém uma
+	// conexão keep-alive para cada chamada simultânea
+	if sr.cfg.ConnMode == config.ConnModePool {
+		opts.MaxConns = (sr.cfg.Concurrency + sr.cfg.PoolSize - 1) / sr.cfg.PoolSize
+	}
+
+	for attempt := 0; ; attempt++ {
+		client, err := rpcclient.Dial(ctx, sr.cfg.ServerAddress, opts)
+		conn := metrics.ConnResult{Reconnect: reconnect, Retry: attempt > 0}
+		switch {
+		case err != nil && ctx.Err() != nil:
+			return nil, err // Conexão cancelada pela interrupção do teste não é uma falha
+		case err != nil:
+			conn.Error, conn.GaveUp = err, attempt == sr.cfg.DialRetries
+			results <- metrics.Result{Conn: &conn}
+			if conn.GaveUp || !sr.sleepBackoff(ctx, attempt) {
+				return nil, err
+			}
+		default:
+			reportConnections(client, results, conn)
+			return client, nil
+		}
+	}
+}
+
+// reportConnections registra as conexões abertas pelo cliente desde a consulta anterior.
+// O cliente net/rpc conecta em dial; o cliente HTTP conecta durante as chamadas, e suas
+// conexões são registradas após cada uma delas
+func reportConnections(client rpcclient.Caller, results chan<- metrics.Result, conn metrics.ConnResult) {
+	for _, stats := range client.TakeConnStats() {
+		c := conn
+		c.Dial, c.TLS, c.Handshake, c.Error = stats.Dial, stats.TLS, stats.Handshake, stats.Err
+		c.Reconnect = c.Reconnect || stats.Reconnect
+		results <- metrics.Result{Conn: &c}
+	}
+}
+
 // collectResults processa e armazena os resultados das requisições
 func (sr *StressRunner) collectResults(results <-chan metrics.Result, done chan<- struct{}) {
 	defer close(done)
@@ -129,68 +358,186 @@ func distributeRequests(workers, total int) (base int, remaining int) {
 	return
 }
 
-// runWorker executa um lote de requisições RPC
-func (sr *StressRunner) runWorker(requests int, results chan<- metrics.Result) {
-	client, err := rpcclient.NewClient(sr.cfg.ServerAddress, sr.cfg.Timeout)
-	if err != nil {
-		log.Printf("Falha na conexão RPC: %v", err)
-		sr.sendConnectionErrors(requests, results, err)
-		return
+// runWorker executa um lote de requisições RPC com as conexões da sessão; intended é o
+// horário agendado da primeira. O lote é encerrado antes do fim se ctx for cancelado ou
+// se não for possível conectar após as novas tentativas, caso em que as requisições
+// restantes falham.
+func (sr *StressRunner) runWorker(ctx context.Context, session *connSession, requests int, intended time.Time, results chan<- metrics.Result) {
+	// Executa o número especificado de requisições
+	for i := 0; i < requests && ctx.Err() == nil; i++ {
+		if i > 0 {
+			intended = time.Now() // Em malha fechada a próxima chamada é agendada ao fim da anterior
+		}
+
+		result, err := sr.execute(ctx, session, intended)
+		if err != nil {
+			if ctx.Err() != nil {
+				return // Conexão cancelada pela interrupção do teste
+			}
+			log.Printf("Falha na conexão RPC: %v", err)
+			results <- result
+			sr.sendConnectionErrors(requests-i-1, results, err)
+			return
+		}
+		results <- result
 	}
-	defer func(client *rpcclient.Client) {
-		if err := client.Close(); err != nil {
-			log.Printf("Erro ao fechar cliente: %v", err)
+}
+
+// execute obtém uma conexão da sessão e executa uma chamada, repetindo-a com backoff
+// enquanto falhar por perda de conexão e houver novas tentativas (-retries). A falha ao
+// conectar, após as novas tentativas, é registrada como erro da chamada e também devolvida.
+func (sr *StressRunner) execute(ctx context.Context, session *connSession, intended time.Time) (metrics.Result, error) {
+	// As novas tentativas repetem o método e os argumentos da requisição
+	m, gen := sr.nextRequest(session.worker)
+	args := m.payload.NewArgs(gen)
+	for retry := 0; ; retry++ {
+		var result metrics.Result
+		client, release, err := session.acquire(ctx)
+		if err != nil {
+			result = connectionFailure(err, 0, m)
+		} else {
+			result = sr.call(client, m, args, intended)
+			reportConnections(client, session.results, metrics.ConnResult{})
+			release(result.Error)
 		}
-	}(client)
+		result.Retries = retry
 
-	// Executa o número especificado de requisições
-	for i := 0; i < requests; i++ {
-		start := time.Now()
-		var reply rpcclient.Reply
+		// Sem reconexão não há conexão para uma nova tentativa
+		if result.Error == nil || !retryable(result.Category) || errors.Is(err, errReconnectDisabled) {
+			return result, err
+		}
+		if retry == sr.cfg.Retries || !sr.sleepBackoff(ctx, retry) {
+			result.GaveUp = sr.cfg.Retries > 0
+			return result, err
+		}
+	}
+}
+
+// idleSession reaproveita uma sessão ociosa, com sua conexão, ou cria uma nova
+func (sr *StressRunner) idleSession(results chan<- metrics.Result) *connSession {
+	select {
+	case session := <-sr.idle:
+		return session
+	default:
+		return sr.newSession(results)
+	}
+}
 
-		// Chamada RPC principal
-		err := client.Call(sr.cfg.RPCMethod, sr.payloadData, &reply)
-		duration := time.Since(start)
+// releaseSession devolve a sessão às ociosas, ou a fecha se já houver sessões suficientes
+func (sr *StressRunner) releaseSession(session *connSession) {
+	select {
+	case sr.idle <- session:
+	default:
+		session.close()
+	}
+}
 
-		// Cria resultado com análise de erro
-		results <- metrics.Result{
-			Duration: duration,
-			Error:    sr.analyzeError(err, &reply),
+// closeConnections fecha as sessões ociosas e as conexões compartilhadas ao fim do teste
+func (sr *StressRunner) closeConnections() {
+	for {
+		select {
+		case session := <-sr.idle:
+			session.close()
+		default:
+			if sr.pool != nil {
+				sr.pool.close()
+			}
+			return
 		}
 	}
 }
 
-// analyzeError processa e classifica erros da chamada RPC
-func (sr *StressRunner) analyzeError(err error, reply *rpcclient.Reply) error {
+// call executa uma única chamada RPC do método m com os argumentos informados e mede
+// o tempo de serviço e o tempo de resposta, este último a partir do horário agendado
+// para o envio
+func (sr *StressRunner) call(client rpcclient.Caller, m *method, args interface{}, intended time.Time) metrics.Result {
+	start := time.Now()
+	reply := m.payload.NewReply()
+
+	// Chamada RPC principal
+	err := client.Call(sr.calls, m.name, args, reply)
+	end := time.Now()
+
+	// Chamada abandonada ao fim do prazo de interrupção: o resultado é desconhecido
+	if errors.Is(err, context.Canceled) {
+		return metrics.Result{Abandoned: true}
+	}
+	return newResult(m, start, intended, end, err, reply)
+}
+
+// newResult monta o resultado de uma chamada concluída do método m, classificando o
+// erro ou validando a resposta
+func newResult(m *method, start, intended, end time.Time, err error, reply interface{}) metrics.Result {
+	result := metrics.Result{
+		Duration:     end.Sub(start),
+		ResponseTime: end.Sub(intended),
+		Method:       m.index,
+	}
 	if err != nil {
-		return categorizeError(err) // Classifica erros de rede
+		result.Error, result.Category = err, classifyError(err) // Classifica a falha para os relatórios
+		result.TimedOut = result.Category == metrics.ErrorTimeout
+	} else if err := validateReply(m.validators, reply); err != nil {
+		result.Error, result.Invalid, result.Category = err, true, metrics.ErrorValidation
 	}
+	return result
+}
 
-	// Verificação rigorosa do resultado
-	expected := sr.payloadData.A * sr.payloadData.B
-	if reply.Result != expected {
-		return fmt.Errorf("resultado incorreto: esperado %d, recebido %d", expected, reply.Result)
+// validateReply submete a resposta a todos os validadores do método
+func validateReply(validators []validate.Validator, reply interface{}) error {
+	if len(validators) == 0 {
+		return nil
+	}
+
+	normalized, err := validate.Normalize(reply)
+	if err != nil {
+		return fmt.Errorf("resposta inválida: %w", err)
+	}
+	for _, v := range validators {
+		if err := v.Validate(normalized); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-// categorizeError classifica os tipos de erro para relatórios
-func categorizeError(err error) error {
+// classifyError classifica o erro de uma chamada para os relatórios
+func classifyError(err error) metrics.ErrorCategory {
+	var serverErr rpc.ServerError
+	var rpcErr *rpcclient.RPCError
+	var opErr *net.OpError
 	var netErr net.Error
-	if errors.As(err, &netErr) {
-		if netErr.Timeout() {
-			return fmt.Errorf("timeout: %w", err)
-		}
-		return fmt.Errorf("erro de rede: %w", err)
+	switch {
+	case errors.Is(err, rpcclient.ErrTimeout):
+		return metrics.ErrorTimeout
+	case errors.Is(err, rpc.ErrShutdown):
+		return metrics.ErrorShutdown
+	case errors.As(err, &serverErr), errors.As(err, &rpcErr):
+		return metrics.ErrorServer
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		return metrics.ErrorDial // Conexão sob demanda do transporte HTTP
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return metrics.ErrorTimeout
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF), errors.As(err, &opErr):
+		return metrics.ErrorReset
+	default:
+		return metrics.ErrorUnknown
 	}
-	return err
 }
 
-// sendConnectionErrors registra falhas de conexão para todas as requisições afetadas
+// connectionFailure monta o resultado de uma requisição do método m que não pôde ser
+// enviada por falta de conexão
+func connectionFailure(err error, stage int, m *method) metrics.Result {
+	category := metrics.ErrorDial
+	if errors.Is(err, errReconnectDisabled) {
+		category = metrics.ErrorShutdown
+	}
+	return metrics.Result{Error: err, Category: category, Stage: stage, Method: m.index}
+}
+
+// sendConnectionErrors registra falhas de conexão para todas as requisições afetadas,
+// sorteando o método de cada uma como se tivessem sido enviadas
 func (sr *StressRunner) sendConnectionErrors(requests int, results chan<- metrics.Result, connErr error) {
 	for i := 0; i < requests; i++ {
-		results <- metrics.Result{
-			Error: fmt.Errorf("falha na conexão: %w", connErr),
-		}
+		results <- connectionFailure(connErr, 0, sr.nextMethod())
 	}
 }
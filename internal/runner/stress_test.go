@@ -0,0 +1,354 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/denner-s/gorpcstress/internal/config"
+	"github.com/denner-s/gorpcstress/internal/metrics"
+	"github.com/denner-s/gorpcstress/pkg/rpcclient"
+	"io"
+	"net"
+	"net/rpc"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// testArithmetic é o serviço Arithmetic do servidor de exemplo, com um atraso opcional
+type testArithmetic struct {
+	delay time.Duration
+}
+
+// Multiply multiplica os operandos após o atraso configurado
+func (a *testArithmetic) Multiply(args *rpcclient.Args, reply *rpcclient.Reply) error {
+	time.Sleep(a.delay)
+	reply.Result = args.A * args.B
+	return nil
+}
+
+// testServer é um servidor net/rpc em processo que conta as conexões recebidas
+type testServer struct {
+	addr  string
+	conns atomic.Int64
+}
+
+// startServer inicia um servidor net/rpc com o serviço Arithmetic, encerrado ao fim do teste
+func startServer(t *testing.T, delay time.Duration) *testServer {
+	t.Helper()
+	server := rpc.NewServer()
+	if err := server.RegisterName("Arithmetic", &testArithmetic{delay: delay}); err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	ts := &testServer{addr: listener.Addr().String()}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			ts.conns.Add(1)
+			go server.ServeConn(conn)
+		}
+	}()
+	return ts
+}
+
+// testConfig cria a configuração de um teste contra o servidor informado
+func testConfig(addr string) *config.Config {
+	return &config.Config{
+		ServerAddress:   addr,
+		RPCMethod:       "Arithmetic.Multiply",
+		TotalRequests:   20,
+		Concurrency:     4,
+		Timeout:         time.Second,
+		Reconnect:       true,
+		RetryBackoff:    time.Millisecond,
+		RetryMaxBackoff: time.Millisecond,
+	}
+}
+
+// newCollector cria um coletor com histogramas de precisão 3 até 1 minuto
+func newCollector() *metrics.Collector {
+	return metrics.NewCollector(metrics.Options{Precision: 3, MaxLatency: time.Minute})
+}
+
+func TestRunModes(t *testing.T) {
+	ts := startServer(t, 0)
+	tests := []struct {
+		name     string
+		rate     float64
+		duration time.Duration
+		sent     int           // Envios esperados (requisições mais descartes)
+		minTime  time.Duration // Duração mínima do teste, imposta pelo cronograma
+	}{
+		{"requisições", 0, 0, 20, 0},
+		{"taxa", 200, 0, 20, 95 * time.Millisecond},
+		{"taxa por duração", 100, 200 * time.Millisecond, 20, 190 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(ts.addr)
+			cfg.Rate, cfg.Duration = tt.rate, tt.duration
+			collector := newCollector()
+			NewStressRunner(cfg, collector).Run(context.Background())
+
+			m := collector.GetMetrics()
+			if m.TotalRequests+m.Dropped != tt.sent || m.Errors != 0 {
+				t.Errorf("%d requisições, %d descartes, %d erros; esperados %d envios sem erros", m.TotalRequests, m.Dropped, m.Errors, tt.sent)
+			}
+			if elapsed := m.EndTime.Sub(m.StartTime); elapsed < tt.minTime {
+				t.Errorf("teste durou %v, menos que os %v do cronograma", elapsed, tt.minTime)
+			}
+			if m.TargetRate != tt.rate {
+				t.Errorf("taxa alvo %g, esperada %g", m.TargetRate, tt.rate)
+			}
+		})
+	}
+}
+
+func TestRateModeDropsWhenWorkersAreBusy(t *testing.T) {
+	// Com um único worker e chamadas mais lentas que o intervalo, a malha aberta descarta envios
+	ts := startServer(t, 30*time.Millisecond)
+	cfg := testConfig(ts.addr)
+	cfg.Rate, cfg.Concurrency = 100, 1
+
+	collector := newCollector()
+	NewStressRunner(cfg, collector).Run(context.Background())
+
+	m := collector.GetMetrics()
+	if m.Dropped == 0 || m.TotalRequests+m.Dropped != 20 {
+		t.Errorf("%d requisições e %d descartes; esperados descartes em 20 envios", m.TotalRequests, m.Dropped)
+	}
+}
+
+func TestDistributeRequests(t *testing.T) {
+	tests := []struct {
+		workers, total  int
+		base, remaining int
+	}{
+		{4, 20, 5, 0},
+		{3, 10, 3, 1},
+		{8, 5, 0, 5},
+	}
+	for _, tt := range tests {
+		if base, remaining := distributeRequests(tt.workers, tt.total); base != tt.base || remaining != tt.remaining {
+			t.Errorf("distributeRequests(%d, %d) = %d, %d; esperado %d, %d", tt.workers, tt.total, base, remaining, tt.base, tt.remaining)
+		}
+	}
+}
+
+func TestCallMeasuresResponseTimeFromIntendedSend(t *testing.T) {
+	ts := startServer(t, 10*time.Millisecond)
+	client, err := rpcclient.NewClient(context.Background(), rpcclient.Target{Network: rpcclient.NetworkTCP, Address: ts.addr}, rpcclient.Options{Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }()
+
+	// Uma chamada agendada há 50ms espera na fila antes do envio: o tempo de resposta
+	// inclui a espera, e o tempo de serviço não
+	sr := NewStressRunner(testConfig(ts.addr), newCollector())
+	sr.calls = context.Background()
+	result := sr.call(client, sr.methods[0], sr.methods[0].payload.Args, time.Now().Add(-50*time.Millisecond))
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if result.Duration < 10*time.Millisecond || result.Duration >= 50*time.Millisecond {
+		t.Errorf("tempo de serviço %v, esperado a partir de 10ms", result.Duration)
+	}
+	if result.ResponseTime < result.Duration+50*time.Millisecond {
+		t.Errorf("tempo de resposta %v não inclui a espera de 50ms (serviço %v)", result.ResponseTime, result.Duration)
+	}
+}
+
+func TestRunInterrupted(t *testing.T) {
+	tests := []struct {
+		name      string
+		grace     time.Duration
+		abandoned bool // Chamadas em voo abandonadas ao fim do prazo
+	}{
+		{"chamadas concluídas no prazo", time.Second, false},
+		{"chamadas abandonadas", 20 * time.Millisecond, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := startServer(t, 200*time.Millisecond)
+			cfg := testConfig(ts.addr)
+			cfg.GracePeriod = tt.grace
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			collector := newCollector()
+			NewStressRunner(cfg, collector).Run(ctx)
+
+			// Cada worker tinha uma chamada em voo quando o teste foi interrompido
+			m := collector.GetMetrics()
+			if !m.Interrupted {
+				t.Error("métricas não foram marcadas como parciais")
+			}
+			if tt.abandoned && (m.Abandoned != cfg.Concurrency || m.TotalRequests != 0) {
+				t.Errorf("%d chamadas abandonadas e %d concluídas; esperadas %d abandonadas", m.Abandoned, m.TotalRequests, cfg.Concurrency)
+			}
+			if !tt.abandoned && (m.Abandoned != 0 || m.TotalRequests != cfg.Concurrency || m.Errors != 0) {
+				t.Errorf("%d chamadas concluídas (%d erros) e %d abandonadas; esperadas %d concluídas", m.TotalRequests, m.Errors, m.Abandoned, cfg.Concurrency)
+			}
+		})
+	}
+}
+
+func TestTimeoutsCounted(t *testing.T) {
+	ts := startServer(t, 100*time.Millisecond)
+	for _, pipeline := range []int{0, 2} {
+		t.Run(fmt.Sprintf("pipeline %d", pipeline), func(t *testing.T) {
+			cfg := testConfig(ts.addr)
+			cfg.TotalRequests, cfg.Concurrency, cfg.Pipeline = 4, 2, pipeline
+			cfg.Timeout = 20 * time.Millisecond
+			collector := newCollector()
+			NewStressRunner(cfg, collector).Run(context.Background())
+
+			// Chamadas abandonadas pelo timeout são erros, contados também à parte
+			m := collector.GetMetrics()
+			if m.TotalRequests != 4 || m.Errors != 4 || m.TimedOut != 4 {
+				t.Errorf("%d requisições, %d erros e %d timeouts; esperados 4 timeouts", m.TotalRequests, m.Errors, m.TimedOut)
+			}
+			if c := m.Categories[metrics.ErrorTimeout]; c == nil || c.Count != 4 || len(m.Categories) != 1 {
+				t.Errorf("falhas por categoria %v, esperados 4 timeouts", m.Categories)
+			}
+		})
+	}
+}
+
+func TestValidationFailuresCountedSeparately(t *testing.T) {
+	ts := startServer(t, 0)
+	tests := []struct {
+		name       string
+		validators []config.ValidatorSpec
+		invalid    int
+	}{
+		{"resultado esperado", nil, 0},
+		{"validador aceita", []config.ValidatorSpec{{Spec: "field:Result=15"}}, 0},
+		{"validador recusa", []config.ValidatorSpec{{Spec: "range:Result=20.."}}, 20},
+		{"validador de outro método", []config.ValidatorSpec{{Method: "Store.PlaceOrder", Spec: "field:Result=0"}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(ts.addr)
+			cfg.Validators = tt.validators
+			collector := newCollector()
+			NewStressRunner(cfg, collector).Run(context.Background())
+
+			// Respostas inválidas não são erros de transporte e ficam fora das latências
+			m := collector.GetMetrics()
+			if m.Invalid != tt.invalid || m.Errors != 0 || int(m.ResponseTimes.Count()) != m.TotalRequests-tt.invalid {
+				t.Errorf("%d inválidas, %d erros e %d latências em %d requisições; esperadas %d inválidas", m.Invalid, m.Errors, m.ResponseTimes.Count(), m.TotalRequests, tt.invalid)
+			}
+		})
+	}
+}
+
+func TestConnectionMetrics(t *testing.T) {
+	ts := startServer(t, 0)
+	tests := []struct {
+		name     string
+		addr     string
+		retries  int // Novas tentativas de conexão (-dial-retries)
+		attempts int
+		failures int
+	}{
+		{"servidor disponível", ts.addr, 2, 4, 0},
+		{"servidor inexistente", "127.0.0.1:1", 0, 4, 4},
+		{"novas tentativas", "127.0.0.1:1", 2, 12, 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(tt.addr)
+			cfg.DialRetries = tt.retries
+			collector := newCollector()
+			NewStressRunner(cfg, collector).Run(context.Background())
+
+			// Cada worker abre uma conexão, medida à parte das requisições
+			c := collector.GetMetrics().Connections
+			if c.Attempts != tt.attempts || c.Failures != tt.failures || int(c.Dials.Count()) != tt.attempts-tt.failures {
+				t.Errorf("%d conexões, %d falhas e %d tempos; esperadas %d conexões e %d falhas", c.Attempts, c.Failures, c.Dials.Count(), tt.attempts, tt.failures)
+			}
+			// Cada worker desiste após esgotar as novas tentativas
+			if giveUps := min(tt.failures, 4); c.GiveUps != giveUps || c.Retries != tt.failures-giveUps {
+				t.Errorf("%d desistências e %d novas tentativas; esperadas %d desistências", c.GiveUps, c.Retries, giveUps)
+			}
+		})
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	syscallErr := func(op string, errno syscall.Errno) *net.OpError {
+		return &net.OpError{Op: op, Net: "tcp", Err: os.NewSyscallError(op, errno)}
+	}
+	tests := []struct {
+		name string
+		err  error
+		want metrics.ErrorCategory
+	}{
+		{"timeout do cliente", fmt.Errorf("%w após 1s", rpcclient.ErrTimeout), metrics.ErrorTimeout},
+		{"timeout de rede", &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}, metrics.ErrorTimeout},
+		{"conexão encerrada", rpc.ErrShutdown, metrics.ErrorShutdown},
+		{"erro net/rpc do servidor", rpc.ServerError("divisão por zero"), metrics.ErrorServer},
+		{"erro JSON-RPC do servidor", fmt.Errorf("chamada: %w", &rpcclient.RPCError{Code: -32601, Message: "método inexistente"}), metrics.ErrorServer},
+		{"conexão recusada", syscallErr("dial", syscall.ECONNREFUSED), metrics.ErrorDial},
+		{"conexão reiniciada", syscallErr("read", syscall.ECONNRESET), metrics.ErrorReset},
+		{"EOF", io.ErrUnexpectedEOF, metrics.ErrorReset},
+		{"erro desconhecido", errors.New("falha"), metrics.ErrorUnknown},
+	}
+	for _, tt := range tests {
+		if got := classifyError(tt.err); got != tt.want {
+			t.Errorf("%s: classifyError(%v) = %v, esperado %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+
+	// Sem reconexão, a falha ao obter a conexão é de uma conexão encerrada
+	if got := connectionFailure(errReconnectDisabled, 0, &method{}).Category; got != metrics.ErrorShutdown {
+		t.Errorf("reconexão desativada classificada como %v", got)
+	}
+	if got := connectionFailure(syscallErr("dial", syscall.ECONNREFUSED), 0, &method{}).Category; got != metrics.ErrorDial {
+		t.Errorf("falha ao conectar classificada como %v", got)
+	}
+}
+
+func TestCheckMethods(t *testing.T) {
+	mix := []config.MethodSpec{{Method: "Users.Get", Weight: 1}, {Method: "Users.List", Weight: 1}}
+	tests := []struct {
+		name     string
+		payload  string
+		methods  []config.MethodSpec
+		specs    []string
+		problems int
+	}{
+		{"padrões", "", nil, nil, 0},
+		{"validadores válidos", "", nil, []string{"field:Result=15", "none"}, 0},
+		{"todos os validadores inválidos", "", nil, []string{"desconhecido:x", "range:Result=abc"}, 2},
+		{"payload inexistente", "nao-existe.json", nil, nil, 1},
+		{"payload inexistente em cada método", "nao-existe.json", mix, nil, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig("localhost:1234")
+			cfg.PayloadFile, cfg.MethodMix = tt.payload, tt.methods
+			for _, spec := range tt.specs {
+				cfg.Validators = append(cfg.Validators, config.ValidatorSpec{Spec: spec})
+			}
+
+			err := CheckMethods(cfg)
+			if problems, _ := err.(config.Problems); len(problems) != tt.problems || (err == nil) != (tt.problems == 0) {
+				t.Errorf("CheckMethods = %v, esperados %d problemas", err, tt.problems)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package report
+
+// Importação de pacotes necessários.
+import (
+	"encoding/json"  // Pacote para leitura de relatórios JSON.
+	"fmt"            // Pacote para formatação de strings.
+	"io"             // Pacote para abstração de leitura e escrita.
+	"math"           // Pacote para funções matemáticas.
+	"text/tabwriter" // Pacote para alinhamento de tabelas.
+
+	// Dependência interna do projeto.
+	"github.com/denner-s/gorpcstress/internal/config" // Limites de regressão.
+)
+
+// Estrutura Delta registra a variação de uma métrica entre a linha de base e a
+// execução atual. Change é relativo (%) para latências e throughput e absoluto
+// (pontos percentuais) para a taxa de erro.
+type Delta struct {
+	Metric   string  // Nome da métrica (ex: "p99").
+	Baseline float64 // Valor na linha de base.
+	Current  float64 // Valor na execução atual.
+	Change   float64 // Variação entre as execuções.
+	Limit    float64 // Piora máxima tolerada, quando HasLimit é verdadeiro.
+	HasLimit bool    // Indica que há um limite configurado para a métrica.
+	Breached bool    // Indica que a piora ultrapassou o limite.
+}
+
+// Estrutura Comparison reúne as variações de todas as métricas comparadas.
+type Comparison struct {
+	Deltas []Delta
+}
+
+// Método Regressed indica se algum limite de regressão foi ultrapassado.
+func (c Comparison) Regressed() bool {
+	for _, d := range c.Deltas {
+		if d.Breached {
+			return true
+		}
+	}
+	return false
+}
+
+// Função ReadDocument lê um relatório JSON, recusando versões de esquema desconhecidas.
+func ReadDocument(r io.Reader) (Document, error) {
+	var doc Document
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return Document{}, fmt.Errorf("relatório JSON inválido: %w", err)
+	}
+	if doc.SchemaVersion != SchemaVersion {
+		return Document{}, fmt.Errorf("versão de esquema %d não suportada (esperada %d)", doc.SchemaVersion, SchemaVersion)
+	}
+	return doc, nil
+}
+
+// Função Compare calcula a variação de throughput, taxa de erro e latências (tempo de
+// resposta) entre duas execuções e verifica os limites de regressão informados.
+func Compare(baseline, current Document, thresholds []config.RegressionThreshold) Comparison {
+	values := func(doc Document) map[string]float64 {
+		return map[string]float64{
+			"rps":        doc.Throughput.RPS,
+			"error_rate": doc.Totals.ErrorRate,
+			"mean":       doc.ResponseTime.MeanUS,
+			"p50":        doc.ResponseTime.P50US,
+			"p90":        doc.ResponseTime.P90US,
+			"p99":        doc.ResponseTime.P99US,
+			"p999":       doc.ResponseTime.P999US,
+			"max":        doc.ResponseTime.MaxUS,
+		}
+	}
+	old, now := values(baseline), values(current)
+
+	var c Comparison
+	for _, metric := range config.RegressionMetrics {
+		d := Delta{Metric: metric, Baseline: old[metric], Current: now[metric]}
+		if metric == "error_rate" {
+			d.Change = d.Current - d.Baseline
+		} else {
+			d.Change = relativeChange(d.Baseline, d.Current)
+		}
+
+		for _, t := range thresholds {
+			if t.Metric != metric {
+				continue
+			}
+			d.HasLimit, d.Limit = true, t.Limit
+
+			// Para throughput a piora é a queda; para as demais métricas, o aumento.
+			worsening := d.Change
+			if metric == "rps" {
+				worsening = -d.Change
+			}
+			d.Breached = worsening > t.Limit
+		}
+		c.Deltas = append(c.Deltas, d)
+	}
+	return c
+}
+
+// Função relativeChange calcula a variação percentual de um valor.
+func relativeChange(baseline, current float64) float64 {
+	if baseline == 0 {
+		if current == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return (current - baseline) / baseline * 100
+}
+
+// Função WriteComparison escreve a tabela de variações entre as execuções.
+func WriteComparison(w io.Writer, c Comparison) {
+	fmt.Fprintln(w, "\n=== Comparação com a Linha de Base ===")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Métrica\tBase\tAtual\tVariação\tLimite\tStatus")
+	for _, d := range c.Deltas {
+		limit, status := "-", "-"
+		if d.HasLimit {
+			limit = formatChange(d.Metric, d.Limit)
+			if d.Metric == "rps" {
+				limit = formatChange(d.Metric, -d.Limit) // O limite de throughput é uma queda.
+			}
+			status = "OK"
+			if d.Breached {
+				status = "REGRESSÃO"
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", d.Metric,
+			formatMetric(d.Metric, d.Baseline), formatMetric(d.Metric, d.Current),
+			formatChange(d.Metric, d.Change), limit, status)
+	}
+	_ = tw.Flush()
+
+	if c.Regressed() {
+		fmt.Fprintln(w, "\nResultado: REGRESSÃO detectada")
+	} else {
+		fmt.Fprintln(w, "\nResultado: sem regressões")
+	}
+}
+
+// Função formatMetric formata o valor de uma métrica conforme sua unidade.
+func formatMetric(metric string, v float64) string {
+	switch metric {
+	case "rps":
+		return fmt.Sprintf("%.2f req/s", v)
+	case "error_rate":
+		return fmt.Sprintf("%.2f%%", v)
+	default:
+		return fmtMicros(v)
+	}
+}
+
+// Função formatChange formata uma variação ou limite conforme a métrica.
+func formatChange(metric string, v float64) string {
+	if metric == "error_rate" {
+		return fmt.Sprintf("%+.2f p.p.", v)
+	}
+	return fmt.Sprintf("%+.2f%%", v)
+}
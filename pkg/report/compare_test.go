@@ -0,0 +1,88 @@
+package report
+
+// Importação de pacotes necessários.
+import (
+	"bytes"   // Pacote para capturar os relatórios escritos.
+	"math"    // Pacote para verificar variações infinitas.
+	"strings" // Pacote para montar relatórios de entrada.
+	"testing" // Pacote de testes.
+
+	// Dependência interna do projeto.
+	"github.com/denner-s/gorpcstress/internal/config" // Limites de regressão.
+)
+
+func TestReadDocument(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, testReportConfig(), testMetrics()); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := ReadDocument(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Totals.Requests != 10 || doc.Totals.Errors != 2 || doc.ResponseTime.MaxUS == 0 {
+		t.Errorf("relatório lido difere do escrito: %+v", doc.Totals)
+	}
+
+	for _, input := range []string{`{"schema_version": 99}`, `{}`, `não é JSON`} {
+		if _, err := ReadDocument(strings.NewReader(input)); err == nil {
+			t.Errorf("ReadDocument(%q) deveria falhar", input)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// testDocument monta um relatório com as métricas comparadas.
+	testDocument := func(rps, errorRate, p99 float64) Document {
+		var doc Document
+		doc.Throughput.RPS = rps
+		doc.Totals.ErrorRate = errorRate
+		doc.ResponseTime.P99US = p99
+		return doc
+	}
+
+	tests := []struct {
+		name       string
+		baseline   Document
+		current    Document
+		thresholds string
+		metric     string
+		change     float64
+		breached   bool
+	}{
+		{"latência dentro do limite", testDocument(100, 0, 1000), testDocument(100, 0, 1090), "p99=10%", "p99", 9, false},
+		{"latência acima do limite", testDocument(100, 0, 1000), testDocument(100, 0, 1200), "p99=10%", "p99", 20, true},
+		{"queda de throughput", testDocument(100, 0, 1000), testDocument(90, 0, 1000), "rps=5%", "rps", -10, true},
+		{"aumento de throughput", testDocument(100, 0, 1000), testDocument(150, 0, 1000), "rps=5%", "rps", 50, false},
+		{"taxa de erro em pontos percentuais", testDocument(100, 1, 1000), testDocument(100, 1.4, 1000), "error_rate=0.5%", "error_rate", 0.4, false},
+		{"taxa de erro acima do limite", testDocument(100, 1, 1000), testDocument(100, 2, 1000), "error_rate=0.5%", "error_rate", 1, true},
+		{"métrica ausente da linha de base", testDocument(100, 0, 0), testDocument(100, 0, 1000), "p99=10%", "p99", math.Inf(1), true},
+		{"métrica ausente nas duas execuções", testDocument(100, 0, 0), testDocument(100, 0, 0), "p99=10%", "p99", 0, false},
+		{"sem limite configurado", testDocument(100, 0, 1000), testDocument(100, 0, 5000), "rps=5%", "p99", 400, false},
+	}
+	for _, tt := range tests {
+		thresholds, err := config.ParseRegressionThresholds(tt.thresholds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := Compare(tt.baseline, tt.current, thresholds)
+		if len(c.Deltas) != len(config.RegressionMetrics) {
+			t.Fatalf("%s: %d variações, esperadas %d", tt.name, len(c.Deltas), len(config.RegressionMetrics))
+		}
+
+		for _, d := range c.Deltas {
+			if d.Metric != tt.metric {
+				continue
+			}
+			if math.Abs(d.Change-tt.change) > 1e-9 && d.Change != tt.change {
+				t.Errorf("%s: variação %v, esperada %v", tt.name, d.Change, tt.change)
+			}
+			if d.Breached != tt.breached {
+				t.Errorf("%s: limite ultrapassado = %v, esperado %v", tt.name, d.Breached, tt.breached)
+			}
+		}
+		if c.Regressed() != tt.breached {
+			t.Errorf("%s: Regressed() = %v, esperado %v", tt.name, c.Regressed(), tt.breached)
+		}
+	}
+}
@@ -2,111 +2,248 @@ package report
 
 // Importação de pacotes necessários.
 import (
-	"fmt"  // Pacote para formatação de strings.
-	"sort" // Pacote para ordenação de slices.
-	"time" // Pacote para manipulação de tempo e durações.
+	"fmt"            // Pacote para formatação de strings.
+	"io"             // Pacote para abstração de escrita.
+	"os"             // Pacote para acesso à saída padrão.
+	"text/tabwriter" // Pacote para alinhamento de tabelas.
+	"time"           // Pacote para manipulação de tempo e durações.
 
 	// Dependência interna do projeto.
 	"github.com/denner-s/gorpcstress/internal/metrics" // Métricas coletadas durante o teste.
 )
 
+// maxSampleMessages limita as mensagens de exemplo exibidas para cada categoria de falha.
+const maxSampleMessages = 3
+
 // Função GenerateReport gera e exibe um relatório de desempenho com base nas métricas coletadas.
 func GenerateReport(m metrics.Metrics) {
-	fmt.Println("\n=== Relatório do Teste de Estresse ===")
+	WriteText(os.Stdout, m)
+}
+
+// Função WriteText escreve o relatório de desempenho em formato texto no writer informado.
+func WriteText(w io.Writer, m metrics.Metrics) {
+	fmt.Fprintln(w, "\n=== Relatório do Teste de Estresse ===")
+	if m.Interrupted {
+		fmt.Fprintln(w, "*** TESTE INTERROMPIDO — resultados parciais ***")
+	}
 
 	// Exibe informações gerais sobre o teste.
-	printGeneralInfo(m)
+	printGeneralInfo(w, m)
 
 	// Exibe métricas de throughput (taxa de transferência).
-	printThroughput(m)
+	printThroughput(w, m)
 
 	// Exibe métricas de latência.
-	printLatencyMetrics(m)
+	printLatencyMetrics(w, m)
+
+	// Exibe os tempos de conexão e de handshake, se houver conexões registradas.
+	printConnections(w, m)
+
+	// Exibe as métricas por estágio do perfil de carga, se houver.
+	printStageBreakdown(w, m)
+
+	// Exibe as métricas por método da mistura de tráfego, se houver.
+	printMethodBreakdown(w, m)
+
+	// Exibe a série temporal, se habilitada.
+	printTimeSeries(w, m)
 }
 
 // Função printGeneralInfo exibe informações gerais sobre o teste.
-func printGeneralInfo(m metrics.Metrics) {
+func printGeneralInfo(w io.Writer, m metrics.Metrics) {
 	// Calcula a duração total do teste.
 	totalDuration := m.EndTime.Sub(m.StartTime)
 
 	// Exibe a duração total, o número total de requisições e o número de erros.
-	fmt.Printf("Tempo total de execução:\t %v\n", totalDuration.Round(time.Millisecond))
-	fmt.Printf("Requisições totais:\t\t %d\n", m.TotalRequests)
-	fmt.Printf("Requisições com erro:\t\t %d (%.2f%%)\n",
-		m.Errors, errorRate(m))
+	fmt.Fprintf(w, "Tempo total de execução:\t %v\n", totalDuration.Round(time.Millisecond))
+	fmt.Fprintf(w, "Requisições totais:\t\t %d\n", m.TotalRequests)
+	fmt.Fprintf(w, "Requisições com erro:\t\t %d (%.2f%%)\n",
+		m.Errors, percentOf(m.Errors, m.TotalRequests))
+	if m.Abandoned+m.TimedOut > 0 {
+		fmt.Fprintf(w, "Chamadas abandonadas em voo:\t %d (%d por timeout, %d na interrupção)\n",
+			m.Abandoned+m.TimedOut, m.TimedOut, m.Abandoned)
+	}
+
+	if r := m.Retries; r.Retries+r.GiveUps > 0 {
+		fmt.Fprintf(w, "Novas tentativas:\t\t %d em %d tentativas (%d requisições falharam em todas)\n",
+			r.Retries, r.Attempts, r.GiveUps)
+	}
+
+	// Falhas de validação são contabilizadas separadamente dos erros de transporte.
+	fmt.Fprintf(w, "Respostas inválidas:\t\t %d (%.2f%%)\n",
+		m.Invalid, percentOf(m.Invalid, m.TotalRequests))
+
+	// Detalha as falhas por categoria, com as mensagens mais frequentes.
+	printErrorCategories(w, m)
+}
+
+// Função printErrorCategories exibe as falhas de cada categoria com algumas mensagens de exemplo.
+func printErrorCategories(w io.Writer, m metrics.Metrics) {
+	categories := errorCategories(m)
+	if len(categories) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "\nFalhas por categoria:")
+	for _, c := range categories {
+		fmt.Fprintf(w, "  %-18s %6d (%.2f%%)\n", c.Label, c.Count, percentOf(c.Count, m.TotalRequests))
+		for _, e := range c.Messages[:min(len(c.Messages), maxSampleMessages)] {
+			fmt.Fprintf(w, "    • %d × %s\n", e.Count, e.Message)
+		}
+		if more := len(c.Messages) - maxSampleMessages; more > 0 {
+			fmt.Fprintf(w, "    • ... e mais %d mensagens distintas\n", more)
+		}
+	}
 }
 
-// Função errorRate calcula a taxa de erro em porcentagem.
+// Função errorRate calcula a taxa de falhas em porcentagem, somando erros e respostas inválidas.
 func errorRate(m metrics.Metrics) float64 {
-	return float64(m.Errors) / float64(m.TotalRequests) * 100
+	return percentOf(m.Errors+m.Invalid, m.TotalRequests)
+}
+
+// Função percentOf calcula a porcentagem de part em relação a total.
+func percentOf(part, total int) float64 {
+	if total == 0 {
+		return 0 // Evita divisão por zero quando nenhuma requisição foi enviada.
+	}
+	return float64(part) / float64(total) * 100
 }
 
 // Função printThroughput exibe métricas de throughput (requisições por segundo e por minuto).
-func printThroughput(m metrics.Metrics) {
+func printThroughput(w io.Writer, m metrics.Metrics) {
+	rps := throughput(m)
+	rpm := rps * 60
+
+	fmt.Fprintln(w, "\nThroughput:")
+	fmt.Fprintf(w, "Requests por segundo (RPS):\t %.2f\n", rps)
+	fmt.Fprintf(w, "Requests por minuto (RPM):\t %.2f\n", rpm)
+
+	// No modo de taxa constante compara a taxa alcançada com a taxa alvo.
+	if m.TargetRate > 0 {
+		fmt.Fprintf(w, "Taxa alvo:\t\t\t %.2f req/s\n", m.TargetRate)
+		fmt.Fprintf(w, "Taxa alcançada:\t\t\t %.2f req/s (%.2f%% do alvo)\n", rps, rps/m.TargetRate*100)
+		fmt.Fprintf(w, "Envios descartados:\t\t %d\n", m.Dropped)
+		fmt.Fprintf(w, "Envios atrasados:\t\t %d\n", m.Late)
+	}
+}
+
+// Função throughput calcula a taxa de requisições por segundo do teste.
+func throughput(m metrics.Metrics) float64 {
 	// Converter duração para segundos com precisão
-	duration := m.EndTime.Sub(m.StartTime)
-	seconds := duration.Seconds()
+	seconds := m.EndTime.Sub(m.StartTime).Seconds()
+	if seconds <= 0 {
+		return 0
+	}
+	return float64(m.TotalRequests) / seconds
+}
+
+// Função printLatencyMetrics exibe métricas de latência.
+func printLatencyMetrics(w io.Writer, m metrics.Metrics) {
+	// Verifica se há durações registradas.
+	if m.Durations.Count() == 0 {
+		fmt.Fprintln(w, "\nSem métricas de latência (todas requisições falharam)")
+		return
+	}
+
+	// O tempo de serviço parte do envio efetivo; o tempo de resposta parte do envio
+	// agendado e expõe a espera causada por paradas do servidor.
+	printLatencyTable(w, "Latência - tempo de serviço", m.Durations)
+	printLatencyTable(w, "Latência - tempo de resposta (desde o envio agendado)", m.ResponseTimes)
+}
+
+// Função printLatencyTable exibe os percentis de um histograma de durações.
+func printLatencyTable(w io.Writer, title string, h *metrics.Histogram) {
+	fmt.Fprintf(w, "\n%s:\n", title)
+	fmt.Fprintf(w, "Média:\t\t %v\n", h.Mean().Round(time.Microsecond))
+	fmt.Fprintf(w, "Desvio padrão:\t %v\n", h.StdDev().Round(time.Microsecond))
+	fmt.Fprintf(w, "Min:\t\t %v\n", h.Min().Round(time.Microsecond))
+	fmt.Fprintf(w, "Max:\t\t %v\n", h.Max().Round(time.Microsecond))
+	fmt.Fprintf(w, "p50 (mediana):\t %v\n", h.Percentile(0.5).Round(time.Microsecond))
+	fmt.Fprintf(w, "p90:\t\t %v\n", h.Percentile(0.9).Round(time.Microsecond))
+	fmt.Fprintf(w, "p99:\t\t %v\n", h.Percentile(0.99).Round(time.Microsecond))
+	fmt.Fprintf(w, "p99.9:\t\t %v\n", h.Percentile(0.999).Round(time.Microsecond))
+}
 
-	var rps, rpm float64
-	if seconds > 0 {
-		rps = float64(m.TotalRequests) / seconds
-		rpm = rps * 60
-	} else {
-		rps = 0
-		rpm = 0
+// Função printConnections exibe as tentativas de conexão e os tempos de conexão TCP e
+// dos handshakes TLS e HTTP CONNECT, medidos separadamente do tempo das requisições.
+func printConnections(w io.Writer, m metrics.Metrics) {
+	conns := m.Connections
+	if conns.Attempts == 0 {
+		return
 	}
 
-	fmt.Println("\nThroughput:")
-	fmt.Printf("Requests por segundo (RPS):\t %.2f\n", rps)
-	fmt.Printf("Requests por minuto (RPM):\t %.2f\n", rpm)
+	fmt.Fprintln(w, "\nConexões:")
+	fmt.Fprintf(w, "Tentativas:\t\t %d (%d falhas, %d reconexões)\n", conns.Attempts, conns.Failures, conns.Reconnects)
+	if conns.Retries > 0 {
+		fmt.Fprintf(w, "Novas tentativas:\t %d (%d conexões abandonadas)\n", conns.Retries, conns.GiveUps)
+	}
+	printConnectionTimes(w, "Conexão:\t\t", conns.Dials)
+	printConnectionTimes(w, "Handshake TLS:\t\t", conns.TLS)
+	printConnectionTimes(w, "Handshake HTTP:\t\t", conns.Handshakes)
 }
 
-// Função percentile calcula o percentil das durações das requisições.
-func percentile(durations []time.Duration, p float64) time.Duration {
-	if len(durations) == 0 {
-		return 0 // Retorna 0 se não houver durações registradas.
+// Função printConnectionTimes exibe o resumo de um histograma de tempos de conexão.
+func printConnectionTimes(w io.Writer, label string, h *metrics.Histogram) {
+	if h.Count() == 0 {
+		return
 	}
+	fmt.Fprintf(w, "%s média %v, p50 %v, p99 %v, max %v\n", label,
+		h.Mean().Round(time.Microsecond),
+		h.Percentile(0.5).Round(time.Microsecond),
+		h.Percentile(0.99).Round(time.Microsecond),
+		h.Max().Round(time.Microsecond))
+}
 
-	// Ordena as durações em ordem crescente.
-	sort.Slice(durations, func(i, j int) bool {
-		return durations[i] < durations[j]
-	})
+// Função printStageBreakdown exibe latência e erros de cada estágio do perfil de carga.
+func printStageBreakdown(w io.Writer, m metrics.Metrics) {
+	if len(m.Stages) == 0 {
+		return
+	}
 
-	// Calcula o índice correspondente ao percentil desejado.
-	index := int(float64(len(durations)-1) * p) // Corrigido para usar len-1
-	return durations[index]                     // Retorna a duração no índice calculado.
+	fmt.Fprintln(w, "\nEstágios:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tEstágio\tReq\tErros\tDescartes\tp50 serviço\tp99 serviço\tp99 resposta")
+	for i, stage := range m.Stages {
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%d (%.2f%%)\t%d\t%v\t%v\t%v\n",
+			i+1, stage.Name, stage.TotalRequests, stage.Errors, stageErrorRate(stage), stage.Dropped,
+			stage.Durations.Percentile(0.5).Round(time.Microsecond),
+			stage.Durations.Percentile(0.99).Round(time.Microsecond),
+			stage.ResponseTimes.Percentile(0.99).Round(time.Microsecond))
+	}
+	_ = tw.Flush()
 }
 
-// Função printLatencyMetrics exibe métricas de latência.
-func printLatencyMetrics(m metrics.Metrics) {
-	// Verifica se há durações registradas.
-	if len(m.Durations) == 0 {
-		fmt.Println("\nSem métricas de latência (todas requisições falharam)")
+// Função printMethodBreakdown exibe a parcela, a latência e as falhas de cada método
+// da mistura de tráfego, com o peso configurado para comparação.
+func printMethodBreakdown(w io.Writer, m metrics.Metrics) {
+	if len(m.Methods) == 0 {
 		return
 	}
 
-	// Cria uma cópia ordenada das durações.
-	sorted := make([]time.Duration, len(m.Durations))
-	copy(sorted, m.Durations)
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i] < sorted[j]
-	})
-
-	// Exibe as métricas de latência.
-	fmt.Println("\nLatência (microssegundos):")
-	fmt.Printf("Média:\t\t %v\n", averageDuration(sorted).Round(time.Microsecond))
-	fmt.Printf("Min:\t\t %v\n", sorted[0].Round(time.Microsecond))
-	fmt.Printf("Max:\t\t %v\n", sorted[len(sorted)-1].Round(time.Microsecond))
-	fmt.Printf("p50 (mediana):\t %v\n", percentile(sorted, 0.5))
-	fmt.Printf("p90:\t\t %v\n", percentile(sorted, 0.9))
-	fmt.Printf("p99:\t\t %v\n", percentile(sorted, 0.99))
+	totalWeight := 0
+	for _, method := range m.Methods {
+		totalWeight += method.Weight
+	}
+
+	fmt.Fprintln(w, "\nMétodos:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Método\tPeso\tReq\tErros\tInválidas\tp50 serviço\tp99 serviço\tp99 resposta")
+	for _, method := range m.Methods {
+		fmt.Fprintf(tw, "%s\t%d (%.1f%%)\t%d (%.1f%%)\t%d\t%d\t%v\t%v\t%v\n",
+			method.Name, method.Weight, percentOf(method.Weight, totalWeight),
+			method.TotalRequests, percentOf(method.TotalRequests, m.TotalRequests),
+			method.Errors, method.Invalid,
+			method.Durations.Percentile(0.5).Round(time.Microsecond),
+			method.Durations.Percentile(0.99).Round(time.Microsecond),
+			method.ResponseTimes.Percentile(0.99).Round(time.Microsecond))
+	}
+	_ = tw.Flush()
 }
 
-// Função averageDuration calcula a duração média das requisições.
-func averageDuration(durations []time.Duration) time.Duration {
-	var total time.Duration
-	for _, d := range durations {
-		total += d // Soma todas as durações.
+// Função stageErrorRate calcula a taxa de erro de um estágio em porcentagem.
+func stageErrorRate(s metrics.StageMetrics) float64 {
+	if s.TotalRequests == 0 {
+		return 0
 	}
-	return total / time.Duration(len(durations)) // Retorna a média.
+	return float64(s.Errors) / float64(s.TotalRequests) * 100
 }
@@ -0,0 +1,294 @@
+package report
+
+// Importação de pacotes necessários.
+import (
+	"fmt"           // Pacote para formatação de strings.
+	"html/template" // Pacote para geração segura de HTML.
+	"io"            // Pacote para abstração de escrita.
+	"strings"       // Pacote para manipulação de strings.
+	"time"          // Pacote para manipulação de tempo e durações.
+
+	// Dependências internas do projeto.
+	"github.com/denner-s/gorpcstress/internal/config"  // Configuração usada no teste.
+	"github.com/denner-s/gorpcstress/internal/metrics" // Métricas coletadas durante o teste.
+)
+
+// Estrutura htmlPage reúne os dados e os gráficos exibidos no relatório HTML.
+type htmlPage struct {
+	Doc        Document
+	Config     [][2]string
+	Throughput template.HTML
+	Latency    template.HTML
+	Histogram  template.HTML
+	Errors     template.HTML
+}
+
+// Função WriteHTML escreve um relatório HTML autocontido: estilos e gráficos SVG são
+// embutidos no arquivo, que pode ser aberto offline e compartilhado diretamente.
+func WriteHTML(w io.Writer, cfg *config.Config, m metrics.Metrics) error {
+	doc := NewDocument(cfg, m)
+
+	offsets := make([]float64, len(doc.TimeSeries))
+	rps := make([]float64, len(doc.TimeSeries))
+	errs := make([]float64, len(doc.TimeSeries))
+	p50 := make([]float64, len(doc.TimeSeries))
+	p90 := make([]float64, len(doc.TimeSeries))
+	p99 := make([]float64, len(doc.TimeSeries))
+	for i, in := range doc.TimeSeries {
+		offsets[i] = in.OffsetS + in.LengthS
+		rps[i] = in.RPS
+		if in.LengthS > 0 {
+			errs[i] = float64(in.Errors) / in.LengthS
+		}
+		p50[i], p90[i], p99[i] = in.P50US, in.P90US, in.P99US
+	}
+
+	page := htmlPage{
+		Doc:    doc,
+		Config: configRows(doc.Config),
+		Throughput: lineChart(offsets, []chartSeries{
+			{Name: "req/s", Color: chartColors[0], Values: rps},
+			{Name: "erros/s", Color: chartColors[2], Values: errs},
+		}, func(v float64) string { return fmt.Sprintf("%.0f", v) }),
+		Latency: lineChart(offsets, []chartSeries{
+			{Name: "p50", Color: chartColors[3], Values: p50},
+			{Name: "p90", Color: chartColors[1], Values: p90},
+			{Name: "p99", Color: chartColors[2], Values: p99},
+		}, func(v float64) string { return fmtMicros(v) }),
+		Histogram: histogramChart(m.ResponseTimes),
+		Errors:    pieChart(categorySlices(doc.Categories)),
+	}
+	return htmlTemplate.Execute(w, page)
+}
+
+// Função configRows lista a configuração do teste como pares nome/valor.
+func configRows(c ConfigInfo) [][2]string {
+	rows := [][2]string{
+		{"Servidor", c.Server},
+		{"Método", c.Method},
+		{"Codec", c.Codec},
+		{"Conexões", connModeDescription(c)},
+		{"Requisições", fmt.Sprint(c.Requests)},
+		{"Concorrência", fmt.Sprint(c.Concurrency)},
+		{"Duração", c.Duration},
+		{"Timeout", c.Timeout},
+		{"Novas tentativas", retryDescription(c.Retry)},
+	}
+	if c.RPCPath != "" {
+		rows = append(rows, [2]string{"Handshake HTTP", c.RPCPath})
+	}
+	if c.TLS != nil {
+		rows = append(rows, [2]string{"TLS", tlsDescription(*c.TLS)})
+	}
+	if c.Rate > 0 {
+		rows = append(rows, [2]string{"Taxa alvo", fmt.Sprintf("%g req/s", c.Rate)})
+	}
+	if c.PayloadFile != "" {
+		rows = append(rows, [2]string{"Payload", c.PayloadFile})
+	}
+	for _, m := range c.Methods {
+		rows = append(rows, [2]string{"Método " + m.Method, methodDescription(m)})
+	}
+	for i, s := range c.Stages {
+		rows = append(rows, [2]string{fmt.Sprintf("Estágio %d (%s)", i+1, c.StageTarget), fmt.Sprintf("%s → %g", s.Duration, s.Target)})
+	}
+	return append(rows,
+		[2]string{"Semente", fmt.Sprint(c.Seed)},
+		[2]string{"Intervalo da série", c.Interval},
+		[2]string{"Histograma", fmt.Sprintf("%d algarismos, até %s", c.HistogramPrecision, c.HistogramMax)},
+	)
+}
+
+// Função methodDescription resume o peso, o payload e os validadores de um método da mistura.
+func methodDescription(m MethodConfig) string {
+	desc := fmt.Sprintf("peso %d", m.Weight)
+	if m.PayloadFile != "" {
+		desc += ", payload " + m.PayloadFile
+	}
+	if len(m.Validators) > 0 {
+		desc += ", validadores " + strings.Join(m.Validators, " ")
+	}
+	return desc
+}
+
+// Função connModeDescription descreve a estratégia de conexão do teste.
+func connModeDescription(c ConfigInfo) string {
+	desc := c.ConnMode
+	if c.PoolSize > 0 {
+		desc += fmt.Sprintf(" (%d conexões)", c.PoolSize)
+	}
+	if c.Pipeline > 1 {
+		desc += fmt.Sprintf(", pipeline de %d chamadas", c.Pipeline)
+	}
+	return desc
+}
+
+// Função retryDescription resume a política de reconexão e de novas tentativas.
+func retryDescription(r RetryInfo) string {
+	desc := fmt.Sprintf("%d por conexão, %d por chamada, backoff %s a %s", r.DialRetries, r.Retries, r.Backoff, r.MaxBackoff)
+	if !r.Reconnect {
+		desc += ", sem reconexão"
+	}
+	return desc
+}
+
+// Função tlsDescription resume as opções de TLS em uma linha.
+func tlsDescription(t TLSInfo) string {
+	desc := "versão mínima " + t.MinVersion
+	if t.Mutual {
+		desc += ", mútuo"
+	}
+	if t.ServerName != "" {
+		desc += ", nome " + t.ServerName
+	}
+	if t.Insecure {
+		desc += ", sem verificação do servidor"
+	}
+	return desc
+}
+
+// Função categorySlices converte as falhas por categoria nas fatias do gráfico de erros.
+func categorySlices(categories []CategoryCount) []ErrorCount {
+	slices := make([]ErrorCount, 0, len(categories))
+	for _, c := range categories {
+		slices = append(slices, ErrorCount{Message: c.Label, Count: c.Count})
+	}
+	return slices
+}
+
+// Função fmtMicros formata uma latência em microssegundos de forma legível.
+func fmtMicros(us float64) string {
+	return (time.Duration(us * float64(time.Microsecond))).Round(time.Microsecond).String()
+}
+
+// htmlTemplate é o modelo do relatório HTML, sem dependências externas.
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"us":   fmtMicros,
+	"date": func(t time.Time) string { return t.Format("2006-01-02 15:04:05 MST") },
+	"pct":  func(v float64) string { return fmt.Sprintf("%.2f%%", v) },
+	"num":  func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"samples": func(errs []ErrorCount) []ErrorCount {
+		return errs[:min(len(errs), maxSampleMessages)]
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Relatório gorpcstress — {{.Doc.Config.Method}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, sans-serif; margin: 0; background: #f3f4f6; color: #111827; }
+header { background: #1e3a8a; color: #fff; padding: 24px 32px; }
+header h1 { margin: 0 0 4px; font-size: 22px; }
+header p { margin: 0; opacity: .8; }
+header p.interrupted { display: inline-block; margin-top: 8px; padding: 4px 10px; border-radius: 4px; background: #dc2626; opacity: 1; font-weight: 600; }
+main { max-width: 1100px; margin: 0 auto; padding: 24px 32px; }
+section { background: #fff; border-radius: 8px; padding: 16px 24px; margin-bottom: 20px; box-shadow: 0 1px 2px rgba(0,0,0,.08); }
+h2 { font-size: 17px; margin: 0 0 12px; }
+.cards { display: grid; grid-template-columns: repeat(auto-fit, minmax(160px, 1fr)); gap: 12px; }
+.card { background: #eff6ff; border-radius: 6px; padding: 12px; }
+.card b { display: block; font-size: 20px; margin-top: 4px; }
+table { border-collapse: collapse; width: 100%; font-size: 14px; }
+th, td { text-align: left; padding: 6px 8px; border-bottom: 1px solid #e5e7eb; }
+td.n, th.n { text-align: right; font-variant-numeric: tabular-nums; }
+svg { width: 100%; height: auto; }
+svg .axis, svg .legend { font-size: 11px; fill: #374151; }
+svg .grid { stroke: #e5e7eb; }
+.empty { color: #6b7280; font-style: italic; }
+.pass { color: #16a34a; font-weight: 600; }
+.fail { color: #dc2626; font-weight: 600; }
+</style>
+</head>
+<body>
+<header>
+<h1>Relatório do Teste de Estresse</h1>
+<p>{{.Doc.Config.Method}} em {{.Doc.Config.Server}} · {{date .Doc.StartTime}} · {{num .Doc.DurationS}}s</p>
+{{if .Doc.Interrupted}}<p class="interrupted">Teste interrompido — resultados parciais{{if .Doc.Totals.Abandoned}} ({{.Doc.Totals.Abandoned}} chamadas abandonadas){{end}}</p>{{end}}
+</header>
+<main>
+<section>
+<h2>Resumo</h2>
+<div class="cards">
+<div class="card">Requisições<b>{{.Doc.Totals.Requests}}</b></div>
+<div class="card">Throughput<b>{{num .Doc.Throughput.RPS}} req/s</b></div>
+{{if .Doc.Throughput.TargetRPS}}<div class="card">Taxa alvo<b>{{num .Doc.Throughput.TargetRPS}} req/s</b></div>{{end}}
+<div class="card">Erros<b>{{.Doc.Totals.Errors}}</b></div>
+{{if .Doc.Totals.TimedOut}}<div class="card">Timeouts<b>{{.Doc.Totals.TimedOut}}</b>abandonadas em voo</div>{{end}}
+<div class="card">Respostas inválidas<b>{{.Doc.Totals.Invalid}}</b></div>
+<div class="card">Taxa de falhas<b>{{pct .Doc.Totals.ErrorRate}}</b></div>
+{{with .Doc.Connections}}<div class="card">Conexões<b>{{.Attempts}}</b>{{.Failures}} falhas, {{.Reconnects}} reconexões</div>{{end}}
+{{with .Doc.Retries}}<div class="card">Novas tentativas<b>{{.Retries}}</b>{{.GiveUps}} requisições falharam em todas</div>{{end}}
+<div class="card">p50 resposta<b>{{us .Doc.ResponseTime.P50US}}</b></div>
+<div class="card">p99 resposta<b>{{us .Doc.ResponseTime.P99US}}</b></div>
+</div>
+</section>
+{{if .Doc.Thresholds}}<section>
+<h2>Critérios de Aprovação</h2>
+<table>
+<tr><th>Critério</th><th class="n">Medido</th><th>Status</th></tr>
+{{range .Doc.Thresholds}}<tr><td>{{.Expr}}</td><td class="n">{{.Actual}}</td><td class="{{if .Passed}}pass{{else}}fail{{end}}">{{if .Passed}}OK{{else}}FALHOU{{end}}</td></tr>
+{{end}}</table>
+</section>{{end}}
+<section>
+<h2>Throughput ao longo do tempo</h2>
+{{.Throughput}}
+</section>
+<section>
+<h2>Percentis de latência ao longo do tempo (tempo de resposta)</h2>
+{{.Latency}}
+</section>
+<section>
+<h2>Latência</h2>
+<table>
+<tr><th></th><th class="n">Média</th><th class="n">Desvio</th><th class="n">Min</th><th class="n">p50</th><th class="n">p90</th><th class="n">p99</th><th class="n">p99.9</th><th class="n">Max</th></tr>
+{{with .Doc.ServiceTime}}<tr><td>Tempo de serviço</td><td class="n">{{us .MeanUS}}</td><td class="n">{{us .StdDevUS}}</td><td class="n">{{us .MinUS}}</td><td class="n">{{us .P50US}}</td><td class="n">{{us .P90US}}</td><td class="n">{{us .P99US}}</td><td class="n">{{us .P999US}}</td><td class="n">{{us .MaxUS}}</td></tr>{{end}}
+{{with .Doc.ResponseTime}}<tr><td>Tempo de resposta</td><td class="n">{{us .MeanUS}}</td><td class="n">{{us .StdDevUS}}</td><td class="n">{{us .MinUS}}</td><td class="n">{{us .P50US}}</td><td class="n">{{us .P90US}}</td><td class="n">{{us .P99US}}</td><td class="n">{{us .P999US}}</td><td class="n">{{us .MaxUS}}</td></tr>{{end}}
+{{with .Doc.Connections}}{{with .Dial}}<tr><td>Conexão</td><td class="n">{{us .MeanUS}}</td><td class="n">{{us .StdDevUS}}</td><td class="n">{{us .MinUS}}</td><td class="n">{{us .P50US}}</td><td class="n">{{us .P90US}}</td><td class="n">{{us .P99US}}</td><td class="n">{{us .P999US}}</td><td class="n">{{us .MaxUS}}</td></tr>{{end}}
+{{with .TLSHandshake}}<tr><td>Handshake TLS</td><td class="n">{{us .MeanUS}}</td><td class="n">{{us .StdDevUS}}</td><td class="n">{{us .MinUS}}</td><td class="n">{{us .P50US}}</td><td class="n">{{us .P90US}}</td><td class="n">{{us .P99US}}</td><td class="n">{{us .P999US}}</td><td class="n">{{us .MaxUS}}</td></tr>{{end}}
+{{with .Handshake}}<tr><td>Handshake HTTP</td><td class="n">{{us .MeanUS}}</td><td class="n">{{us .StdDevUS}}</td><td class="n">{{us .MinUS}}</td><td class="n">{{us .P50US}}</td><td class="n">{{us .P90US}}</td><td class="n">{{us .P99US}}</td><td class="n">{{us .P999US}}</td><td class="n">{{us .MaxUS}}</td></tr>{{end}}{{end}}
+</table>
+<h2 style="margin-top:20px">Distribuição do tempo de resposta</h2>
+{{.Histogram}}
+</section>
+{{if .Doc.Stages}}<section>
+<h2>Estágios</h2>
+<table>
+<tr><th>Estágio</th><th class="n">Req</th><th class="n">Erros</th><th class="n">Descartes</th><th class="n">p50 serviço</th><th class="n">p99 serviço</th><th class="n">p99 resposta</th></tr>
+{{range .Doc.Stages}}<tr><td>{{.Name}}</td><td class="n">{{.Requests}}</td><td class="n">{{.Errors}}</td><td class="n">{{.Dropped}}</td><td class="n">{{us .ServiceTime.P50US}}</td><td class="n">{{us .ServiceTime.P99US}}</td><td class="n">{{us .ResponseTime.P99US}}</td></tr>
+{{end}}</table>
+</section>{{end}}
+{{if .Doc.Methods}}<section>
+<h2>Métodos</h2>
+<table>
+<tr><th>Método</th><th class="n">Peso</th><th class="n">Req</th><th class="n">Parcela</th><th class="n">Erros</th><th class="n">Inválidas</th><th class="n">p50 serviço</th><th class="n">p99 serviço</th><th class="n">p99 resposta</th></tr>
+{{range .Doc.Methods}}<tr><td>{{.Method}}</td><td class="n">{{.Weight}}</td><td class="n">{{.Requests}}</td><td class="n">{{pct .SharePct}}</td><td class="n">{{.Errors}}</td><td class="n">{{.Invalid}}</td><td class="n">{{us .ServiceTime.P50US}}</td><td class="n">{{us .ServiceTime.P99US}}</td><td class="n">{{us .ResponseTime.P99US}}</td></tr>
+{{end}}</table>
+</section>{{end}}
+<section>
+<h2>Erros</h2>
+{{.Errors}}
+{{if .Doc.Categories}}<table>
+<tr><th>Categoria</th><th class="n">Ocorrências</th><th>Exemplos</th></tr>
+{{range .Doc.Categories}}<tr><td>{{.Label}}</td><td class="n">{{.Count}}</td><td>{{range samples .Messages}}{{.Count}} × {{.Message}}<br>{{end}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Doc.Errors}}<h2 style="margin-top:20px">Mensagens de erro</h2>
+<table>
+<tr><th>Mensagem</th><th class="n">Ocorrências</th></tr>
+{{range .Doc.Errors}}<tr><td>{{.Message}}</td><td class="n">{{.Count}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Doc.Invalid}}<h2 style="margin-top:20px">Falhas de validação</h2>
+<table>
+<tr><th>Mensagem</th><th class="n">Ocorrências</th></tr>
+{{range .Doc.Invalid}}<tr><td>{{.Message}}</td><td class="n">{{.Count}}</td></tr>
+{{end}}</table>{{end}}
+</section>
+<section>
+<h2>Configuração</h2>
+<table>
+{{range .Config}}<tr><th>{{index . 0}}</th><td>{{index . 1}}</td></tr>
+{{end}}</table>
+</section>
+</main>
+</body>
+</html>
+`))
@@ -0,0 +1,97 @@
+package report
+
+// Importação de pacotes necessários.
+import (
+	"bytes"   // Pacote para capturar os relatórios escritos.
+	"errors"  // Pacote para criar os erros das requisições sintéticas.
+	"fmt"     // Pacote para formatação das mensagens de erro.
+	"strings" // Pacote para inspecionar o HTML gerado.
+	"testing" // Pacote de testes.
+	"time"    // Pacote para manipulação de tempo e durações.
+
+	// Dependência interna do projeto.
+	"github.com/denner-s/gorpcstress/internal/metrics" // Métricas coletadas durante o teste.
+)
+
+func TestWriteHTMLIsSelfContained(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, testReportConfig(), testMetrics()); err != nil {
+		t.Fatal(err)
+	}
+	page := buf.String()
+
+	// Gráficos de throughput, latência, histograma e erros embutidos em SVG
+	if n := strings.Count(page, "<svg"); n != 4 {
+		t.Errorf("%d gráficos SVG, esperados 4", n)
+	}
+	for _, external := range []string{"<script", "<link", `src="`, "@import", "url("} {
+		if strings.Contains(page, external) {
+			t.Errorf("relatório depende de recurso externo: %q", external)
+		}
+	}
+	for _, text := range []string{"localhost:1234", "Arithmetic.Multiply", "falha na conexão"} {
+		if !strings.Contains(page, text) {
+			t.Errorf("relatório não contém %q", text)
+		}
+	}
+}
+
+func TestWriteHTMLEscapesErrors(t *testing.T) {
+	c := metrics.NewCollector(metrics.Options{Precision: 3, MaxLatency: time.Minute})
+	c.Start()
+	c.RecordResult(metrics.Result{Error: errors.New(`<script>alert("x")</script>`)})
+	c.Stop()
+
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, testReportConfig(), c.GetMetrics()); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "<script>") {
+		t.Error("mensagem de erro inserida sem escape no relatório")
+	}
+	// Sem série temporal e sem sucessos, os gráficos dão lugar a mensagens
+	if !strings.Contains(buf.String(), `class="empty"`) {
+		t.Error("gráficos sem dados deveriam exibir uma mensagem")
+	}
+}
+
+func TestPieChartGroupsSmallSlices(t *testing.T) {
+	tests := []struct {
+		errors int
+		slices int
+		other  bool
+	}{
+		{0, 0, false},
+		{1, 1, false},
+		{8, 8, false},
+		{12, 8, true},
+	}
+	for _, tt := range tests {
+		var errs []ErrorCount
+		for i := 0; i < tt.errors; i++ {
+			errs = append(errs, ErrorCount{Message: fmt.Sprintf("erro %d", i), Count: tt.errors - i})
+		}
+		chart := string(pieChart(errs))
+		slices := strings.Count(chart, "<path") + strings.Count(chart, "<circle")
+		if slices != tt.slices || strings.Contains(chart, metrics.OtherErrors) != tt.other {
+			t.Errorf("%d erros: %d fatias; esperadas %d (agrupando outros: %v)", tt.errors, slices, tt.slices, tt.other)
+		}
+	}
+}
+
+func TestAbbreviate(t *testing.T) {
+	tests := []struct {
+		text  string
+		limit int
+		want  string
+	}{
+		{"curto", 10, "curto"},
+		{"exatamente", 10, "exatamente"},
+		{"conexão recusada", 8, "conexão…"},
+	}
+	for _, tt := range tests {
+		if got := abbreviate(tt.text, tt.limit); got != tt.want {
+			t.Errorf("abbreviate(%q, %d) = %q, esperado %q", tt.text, tt.limit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,445 @@
+package report
+
+// Importação de pacotes necessários.
+import (
+	"encoding/json" // Pacote para serialização em JSON.
+	"io"            // Pacote para abstração de escrita.
+	"sort"          // Pacote para ordenação de slices.
+	"time"          // Pacote para manipulação de tempo e durações.
+
+	// Dependências internas do projeto.
+	"github.com/denner-s/gorpcstress/internal/config"  // Configuração usada no teste.
+	"github.com/denner-s/gorpcstress/internal/metrics" // Métricas coletadas durante o teste.
+)
+
+// SchemaVersion identifica a versão do formato JSON do relatório. Campos novos podem
+// ser acrescentados sem alterar a versão; remover um campo ou mudar seu significado
+// exige incrementá-la.
+const SchemaVersion = 1
+
+// Estrutura Document é o relatório completo em formato legível por máquina.
+// Latências são expressas em microssegundos e taxas em porcentagem.
+type Document struct {
+	SchemaVersion int          `json:"schema_version"`
+	Tool          string       `json:"tool"`
+	Interrupted   bool         `json:"interrupted"`
+	Config        ConfigInfo   `json:"config"`
+	StartTime     time.Time    `json:"start_time"`
+	EndTime       time.Time    `json:"end_time"`
+	DurationS     float64      `json:"duration_s"`
+	Totals        Totals       `json:"totals"`
+	Throughput    Throughput   `json:"throughput"`
+	Errors        []ErrorCount `json:"errors"`
+	Invalid       []ErrorCount `json:"validation_failures"`
+	ServiceTime   Latency      `json:"service_time"`
+	ResponseTime  Latency      `json:"response_time"`
+	Connections   *Connections `json:"connections,omitempty"`
+	Retries       *Retries     `json:"retries,omitempty"`
+	Stages        []StageInfo  `json:"stages,omitempty"`
+	Methods       []MethodInfo `json:"methods,omitempty"`
+	TimeSeries    []Interval   `json:"time_series,omitempty"`
+
+	Categories []CategoryCount   `json:"error_categories,omitempty"`
+	Thresholds []ThresholdResult `json:"thresholds,omitempty"`
+}
+
+// Estrutura ConfigInfo registra a configuração usada no teste.
+type ConfigInfo struct {
+	Server             string        `json:"server"`
+	Method             string        `json:"method"`
+	Codec              string        `json:"codec"`
+	BatchSize          int           `json:"batch_size,omitempty"`
+	ConnMode           string        `json:"conn_mode"`
+	PoolSize           int           `json:"pool_size,omitempty"`
+	Pipeline           int           `json:"pipeline"`
+	RPCPath            string        `json:"rpc_path,omitempty"`
+	TLS                *TLSInfo      `json:"tls,omitempty"`
+	Retry              RetryInfo     `json:"retry"`
+	Requests           int           `json:"requests"`
+	Concurrency        int           `json:"concurrency"`
+	Duration           string        `json:"duration"`
+	Timeout            string        `json:"timeout"`
+	Rate               float64       `json:"rate"`
+	PayloadFile        string        `json:"payload_file,omitempty"`
+	Validators         []string      `json:"validators,omitempty"`
+	Stages             []StageConfig `json:"stages,omitempty"`
+	StageTarget        string        `json:"stage_target,omitempty"`
+	Interval           string        `json:"interval"`
+	HistogramPrecision int           `json:"hdr_precision"`
+	HistogramMax       string        `json:"hdr_max"`
+
+	Methods []MethodConfig `json:"methods,omitempty"`
+	Seed    int64          `json:"seed"`
+}
+
+// Estrutura TLSInfo registra as opções de TLS usadas no teste.
+type TLSInfo struct {
+	MinVersion string `json:"min_version"`
+	ServerName string `json:"server_name,omitempty"`
+	Mutual     bool   `json:"mutual"`
+	Insecure   bool   `json:"insecure,omitempty"`
+}
+
+// Estrutura RetryInfo registra a política de reconexão e de novas tentativas.
+type RetryInfo struct {
+	Reconnect   bool   `json:"reconnect"`
+	DialRetries int    `json:"dial_retries"`
+	Retries     int    `json:"retries"`
+	Backoff     string `json:"backoff"`
+	MaxBackoff  string `json:"max_backoff"`
+}
+
+// Estrutura StageConfig registra um estágio configurado do perfil de carga.
+type StageConfig struct {
+	Duration string  `json:"duration"`
+	Target   float64 `json:"target"`
+}
+
+// Estrutura MethodConfig registra um método configurado da mistura de tráfego.
+type MethodConfig struct {
+	Method      string   `json:"method"`
+	Weight      int      `json:"weight"`
+	PayloadFile string   `json:"payload_file,omitempty"`
+	Validators  []string `json:"validators,omitempty"`
+}
+
+// Estrutura Totals registra os totais de requisições do teste.
+type Totals struct {
+	Requests  int     `json:"requests"`
+	Successes int64   `json:"successes"`
+	Errors    int     `json:"errors"`
+	Invalid   int     `json:"invalid"`
+	ErrorRate float64 `json:"error_rate_pct"` // Inclui erros e respostas inválidas.
+	Dropped   int     `json:"dropped"`
+	Late      int     `json:"late"`
+	Abandoned int     `json:"abandoned"`
+	TimedOut  int     `json:"timed_out"` // Chamadas abandonadas em voo pelo timeout; já contadas em Errors.
+}
+
+// Estrutura Throughput registra a taxa alcançada e, quando houver, a taxa alvo.
+type Throughput struct {
+	RPS       float64 `json:"rps"`
+	TargetRPS float64 `json:"target_rps,omitempty"`
+}
+
+// Estrutura ErrorCount registra o número de ocorrências de uma mensagem de erro.
+type ErrorCount struct {
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+// Estrutura CategoryCount registra as falhas de uma categoria e suas mensagens de erro.
+type CategoryCount struct {
+	Category string       `json:"category"`
+	Label    string       `json:"label"`
+	Count    int          `json:"count"`
+	Messages []ErrorCount `json:"messages"`
+}
+
+// Estrutura Latency resume uma distribuição de latências. O histograma completo é
+// incluído nos totais do teste para permitir recalcular qualquer percentil.
+type Latency struct {
+	Count     int64              `json:"count"`
+	MinUS     float64            `json:"min_us"`
+	MeanUS    float64            `json:"mean_us"`
+	StdDevUS  float64            `json:"stddev_us"`
+	MaxUS     float64            `json:"max_us"`
+	P50US     float64            `json:"p50_us"`
+	P90US     float64            `json:"p90_us"`
+	P99US     float64            `json:"p99_us"`
+	P999US    float64            `json:"p999_us"`
+	Histogram *metrics.Histogram `json:"histogram,omitempty"`
+}
+
+// Estrutura Connections registra as conexões estabelecidas pelos workers. Os tempos
+// dos handshakes TLS e HTTP CONNECT são medidos separadamente da conexão TCP.
+type Connections struct {
+	Attempts     int      `json:"attempts"`
+	Failures     int      `json:"failures"`
+	Reconnects   int      `json:"reconnects"`
+	Retries      int      `json:"retries"`
+	GiveUps      int      `json:"give_ups"`
+	Dial         Latency  `json:"dial"`
+	TLSHandshake *Latency `json:"tls_handshake,omitempty"`
+	Handshake    *Latency `json:"handshake,omitempty"`
+}
+
+// Estrutura Retries registra as tentativas das chamadas com -retries.
+type Retries struct {
+	Attempts int `json:"attempts"`
+	Retries  int `json:"retries"`
+	GiveUps  int `json:"give_ups"`
+}
+
+// Estrutura StageInfo registra as métricas de um estágio do perfil de carga.
+type StageInfo struct {
+	Name         string  `json:"name"`
+	Requests     int     `json:"requests"`
+	Errors       int     `json:"errors"`
+	Dropped      int     `json:"dropped"`
+	ServiceTime  Latency `json:"service_time"`
+	ResponseTime Latency `json:"response_time"`
+}
+
+// Estrutura MethodInfo registra as métricas de um método da mistura de tráfego.
+type MethodInfo struct {
+	Method       string  `json:"method"`
+	Weight       int     `json:"weight"`
+	Requests     int     `json:"requests"`
+	SharePct     float64 `json:"share_pct"` // Parcela das requisições do teste.
+	Errors       int     `json:"errors"`
+	Invalid      int     `json:"invalid"`
+	ErrorRate    float64 `json:"error_rate_pct"` // Inclui erros e respostas inválidas.
+	ServiceTime  Latency `json:"service_time"`
+	ResponseTime Latency `json:"response_time"`
+}
+
+// Estrutura Interval registra um intervalo da série temporal.
+type Interval struct {
+	OffsetS  float64 `json:"offset_s"`
+	LengthS  float64 `json:"length_s"`
+	Requests int     `json:"requests"`
+	RPS      float64 `json:"rps"`
+	Errors   int     `json:"errors"`
+	Dropped  int     `json:"dropped"`
+	P50US    float64 `json:"p50_us"`
+	P90US    float64 `json:"p90_us"`
+	P99US    float64 `json:"p99_us"`
+	MaxUS    float64 `json:"max_us"`
+}
+
+// Função NewDocument monta o relatório legível por máquina a partir da configuração
+// e das métricas coletadas.
+func NewDocument(cfg *config.Config, m metrics.Metrics) Document {
+	doc := Document{
+		SchemaVersion: SchemaVersion,
+		Tool:          "gorpcstress",
+		Interrupted:   m.Interrupted,
+		Config:        newConfigInfo(cfg),
+		StartTime:     m.StartTime,
+		EndTime:       m.EndTime,
+		DurationS:     m.EndTime.Sub(m.StartTime).Seconds(),
+		Totals: Totals{
+			Requests:  m.TotalRequests,
+			Successes: m.Durations.Count(),
+			Errors:    m.Errors,
+			Invalid:   m.Invalid,
+			ErrorRate: errorRate(m),
+			Dropped:   m.Dropped,
+			Late:      m.Late,
+			Abandoned: m.Abandoned,
+			TimedOut:  m.TimedOut,
+		},
+		Throughput: Throughput{
+			RPS:       throughput(m),
+			TargetRPS: m.TargetRate,
+		},
+		Errors:       sortedErrors(m.ErrorCounts),
+		Invalid:      sortedErrors(m.InvalidCounts),
+		Categories:   errorCategories(m),
+		ServiceTime:  newLatency(m.Durations, true),
+		ResponseTime: newLatency(m.ResponseTimes, true),
+	}
+
+	if conns := m.Connections; conns.Attempts > 0 {
+		doc.Connections = &Connections{
+			Attempts:   conns.Attempts,
+			Failures:   conns.Failures,
+			Reconnects: conns.Reconnects,
+			Retries:    conns.Retries,
+			GiveUps:    conns.GiveUps,
+			Dial:       newLatency(conns.Dials, false),
+		}
+		if conns.TLS.Count() > 0 {
+			tlsHandshake := newLatency(conns.TLS, false)
+			doc.Connections.TLSHandshake = &tlsHandshake
+		}
+		if conns.Handshakes.Count() > 0 {
+			handshake := newLatency(conns.Handshakes, false)
+			doc.Connections.Handshake = &handshake
+		}
+	}
+
+	if cfg.Retries > 0 {
+		doc.Retries = &Retries{
+			Attempts: m.Retries.Attempts,
+			Retries:  m.Retries.Retries,
+			GiveUps:  m.Retries.GiveUps,
+		}
+	}
+
+	for _, stage := range m.Stages {
+		doc.Stages = append(doc.Stages, StageInfo{
+			Name:         stage.Name,
+			Requests:     stage.TotalRequests,
+			Errors:       stage.Errors,
+			Dropped:      stage.Dropped,
+			ServiceTime:  newLatency(stage.Durations, false),
+			ResponseTime: newLatency(stage.ResponseTimes, false),
+		})
+	}
+
+	for _, method := range m.Methods {
+		doc.Methods = append(doc.Methods, MethodInfo{
+			Method:       method.Name,
+			Weight:       method.Weight,
+			Requests:     method.TotalRequests,
+			SharePct:     percentOf(method.TotalRequests, m.TotalRequests),
+			Errors:       method.Errors,
+			Invalid:      method.Invalid,
+			ErrorRate:    percentOf(method.Errors+method.Invalid, method.TotalRequests),
+			ServiceTime:  newLatency(method.Durations, false),
+			ResponseTime: newLatency(method.ResponseTimes, false),
+		})
+	}
+
+	for _, in := range m.TimeSeries {
+		doc.TimeSeries = append(doc.TimeSeries, Interval{
+			OffsetS:  in.Offset.Seconds(),
+			LengthS:  in.Length.Seconds(),
+			Requests: in.Requests,
+			RPS:      intervalRate(in),
+			Errors:   in.Errors,
+			Dropped:  in.Dropped,
+			P50US:    micros(in.P50),
+			P90US:    micros(in.P90),
+			P99US:    micros(in.P99),
+			MaxUS:    micros(in.Max),
+		})
+	}
+
+	if len(cfg.Thresholds) > 0 {
+		doc.Thresholds = EvaluateThresholds(cfg.Thresholds, m)
+	}
+
+	return doc
+}
+
+// Função WriteJSON escreve o relatório em formato JSON no writer informado.
+func WriteJSON(w io.Writer, cfg *config.Config, m metrics.Metrics) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false) // Mantém expressões como "p99 < 50ms" legíveis.
+	return encoder.Encode(NewDocument(cfg, m))
+}
+
+// Função newConfigInfo converte a configuração para o formato do relatório.
+func newConfigInfo(cfg *config.Config) ConfigInfo {
+	info := ConfigInfo{
+		Server:             cfg.ServerAddress,
+		Method:             cfg.MethodNames(),
+		Codec:              cfg.Codec,
+		BatchSize:          cfg.BatchSize,
+		ConnMode:           cfg.ConnMode,
+		Pipeline:           cfg.Pipeline,
+		Requests:           cfg.TotalRequests,
+		Concurrency:        cfg.Concurrency,
+		Duration:           cfg.Duration.String(),
+		Timeout:            cfg.Timeout.String(),
+		Rate:               cfg.Rate,
+		PayloadFile:        cfg.PayloadFile,
+		Interval:           cfg.Interval.String(),
+		HistogramPrecision: cfg.HistogramPrecision,
+		HistogramMax:       cfg.HistogramMax.String(),
+		Seed:               cfg.Seed,
+	}
+
+	info.Retry = RetryInfo{
+		Reconnect:   cfg.Reconnect,
+		DialRetries: cfg.DialRetries,
+		Retries:     cfg.Retries,
+		Backoff:     cfg.RetryBackoff.String(),
+		MaxBackoff:  cfg.RetryMaxBackoff.String(),
+	}
+	if len(cfg.MethodMix) > 0 {
+		for _, m := range cfg.MethodMix {
+			info.Methods = append(info.Methods, MethodConfig{
+				Method:      m.Method,
+				Weight:      m.Weight,
+				PayloadFile: m.PayloadFile,
+				Validators:  cfg.ValidatorsFor(m.Method),
+			})
+		}
+	} else {
+		info.Validators = cfg.ValidatorsFor(cfg.RPCMethod)
+	}
+	if cfg.ConnMode == config.ConnModePool {
+		info.PoolSize = cfg.PoolSize
+	}
+	if cfg.HTTPConnect {
+		info.RPCPath = cfg.RPCPath
+	}
+	if cfg.UsesTLS() {
+		info.TLS = &TLSInfo{
+			MinVersion: cfg.TLSMinVersion,
+			ServerName: cfg.TLSServerName,
+			Mutual:     cfg.TLSCertFile != "",
+			Insecure:   cfg.TLSInsecure,
+		}
+	}
+	if len(cfg.Stages) > 0 {
+		info.StageTarget = cfg.StageTarget
+		for _, stage := range cfg.Stages {
+			info.Stages = append(info.Stages, StageConfig{Duration: stage.Duration.String(), Target: stage.Target})
+		}
+	}
+	return info
+}
+
+// Função newLatency resume um histograma de latências.
+func newLatency(h *metrics.Histogram, withHistogram bool) Latency {
+	latency := Latency{
+		Count:    h.Count(),
+		MinUS:    micros(h.Min()),
+		MeanUS:   micros(h.Mean()),
+		StdDevUS: micros(h.StdDev()),
+		MaxUS:    micros(h.Max()),
+		P50US:    micros(h.Percentile(0.5)),
+		P90US:    micros(h.Percentile(0.9)),
+		P99US:    micros(h.Percentile(0.99)),
+		P999US:   micros(h.Percentile(0.999)),
+	}
+	if withHistogram {
+		latency.Histogram = h
+	}
+	return latency
+}
+
+// Função sortedErrors ordena as mensagens de erro pela frequência, da maior para a menor.
+func sortedErrors(counts map[string]int) []ErrorCount {
+	errs := make([]ErrorCount, 0, len(counts))
+	for message, count := range counts {
+		errs = append(errs, ErrorCount{Message: message, Count: count})
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		if errs[i].Count != errs[j].Count {
+			return errs[i].Count > errs[j].Count
+		}
+		return errs[i].Message < errs[j].Message
+	})
+	return errs
+}
+
+// Função errorCategories lista as categorias com falhas, na ordem de exibição, com as
+// mensagens de cada uma ordenadas por frequência.
+func errorCategories(m metrics.Metrics) []CategoryCount {
+	var categories []CategoryCount
+	for _, category := range metrics.ErrorCategories {
+		c, ok := m.Categories[category]
+		if !ok {
+			continue
+		}
+		categories = append(categories, CategoryCount{
+			Category: string(category),
+			Label:    category.Label(),
+			Count:    c.Count,
+			Messages: sortedErrors(c.Messages),
+		})
+	}
+	return categories
+}
+
+// Função micros converte uma duração para microssegundos.
+func micros(d time.Duration) float64 {
+	return float64(d) / float64(time.Microsecond)
+}
@@ -0,0 +1,103 @@
+package report
+
+// Importação de pacotes necessários.
+import (
+	"bytes"         // Pacote para capturar os relatórios escritos.
+	"encoding/json" // Pacote para decodificar os relatórios JSON.
+	"errors"        // Pacote para criar os erros das requisições sintéticas.
+	"testing"       // Pacote de testes.
+	"time"          // Pacote para manipulação de tempo e durações.
+
+	// Dependências internas do projeto.
+	"github.com/denner-s/gorpcstress/internal/config"  // Configuração usada no teste.
+	"github.com/denner-s/gorpcstress/internal/metrics" // Métricas coletadas durante o teste.
+)
+
+// testMetrics coleta as métricas de um teste sintético com 8 sucessos e 2 erros.
+func testMetrics() metrics.Metrics {
+	c := metrics.NewCollector(metrics.Options{Precision: 3, MaxLatency: time.Minute, Interval: time.Second})
+	c.Start()
+	for i := 1; i <= 8; i++ {
+		d := time.Duration(i) * time.Millisecond
+		c.RecordResult(metrics.Result{Duration: d, ResponseTime: 2 * d})
+	}
+	c.RecordResult(metrics.Result{Error: errors.New("falha na conexão"), Category: metrics.ErrorDial})
+	c.RecordResult(metrics.Result{Error: errors.New("falha na conexão"), Category: metrics.ErrorDial})
+	c.RecordResult(metrics.Result{Dropped: true})
+	c.Stop()
+	return c.GetMetrics()
+}
+
+// testReportConfig devolve a configuração registrada nos relatórios de teste.
+func testReportConfig() *config.Config {
+	return &config.Config{
+		ServerAddress:      "localhost:1234",
+		RPCMethod:          "Arithmetic.Multiply",
+		TotalRequests:      10,
+		Concurrency:        2,
+		Timeout:            time.Second,
+		Interval:           time.Second,
+		HistogramPrecision: 3,
+		HistogramMax:       time.Minute,
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	m := testMetrics()
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, testReportConfig(), m); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nomes dos campos do esquema versionado
+	var raw map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"schema_version", "tool", "config", "totals", "throughput", "errors", "service_time", "response_time", "time_series"} {
+		if _, ok := raw[field]; !ok {
+			t.Errorf("campo %q ausente do relatório", field)
+		}
+	}
+
+	var doc Document
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"schema_version", doc.SchemaVersion, SchemaVersion},
+		{"config.server", doc.Config.Server, "localhost:1234"},
+		{"config.timeout", doc.Config.Timeout, "1s"},
+		{"totals", doc.Totals, Totals{Requests: 10, Successes: 8, Errors: 2, ErrorRate: 20, Dropped: 1}},
+		{"errors", doc.Errors, []ErrorCount{{Message: "falha na conexão", Count: 2}}},
+		{"error_categories", doc.Categories, []CategoryCount{{Category: "dial", Label: "falha de conexão", Count: 2, Messages: []ErrorCount{{Message: "falha na conexão", Count: 2}}}}},
+		{"response_time.count", doc.ResponseTime.Count, int64(8)},
+		{"response_time.max_us", doc.ResponseTime.MaxUS, 16000.0},
+		{"service_time.min_us", doc.ServiceTime.MinUS, 1000.0},
+		{"response_time.p50_us", doc.ResponseTime.P50US, micros(m.ResponseTimes.Percentile(0.5))},
+	}
+	for _, tt := range tests {
+		if !jsonEqual(tt.got, tt.want) {
+			t.Errorf("%s = %+v, esperado %+v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	// O histograma completo permite recalcular qualquer percentil
+	h := doc.ResponseTime.Histogram
+	if h == nil || h.Count() != 8 || h.Percentile(0.99) != m.ResponseTimes.Percentile(0.99) {
+		t.Errorf("histograma dos tempos de resposta não preservado: %+v", h)
+	}
+	if len(doc.TimeSeries) == 0 || doc.TimeSeries[0].Requests != 10 {
+		t.Errorf("série temporal = %+v", doc.TimeSeries)
+	}
+}
+
+// jsonEqual compara dois valores pela sua representação JSON.
+func jsonEqual(a, b interface{}) bool {
+	x, errX := json.Marshal(a)
+	y, errY := json.Marshal(b)
+	return errX == nil && errY == nil && bytes.Equal(x, y)
+}
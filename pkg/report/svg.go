@@ -0,0 +1,223 @@
+package report
+
+// Importação de pacotes necessários.
+import (
+	"fmt"           // Pacote para formatação de strings.
+	"html/template" // Pacote para marcação segura de HTML.
+	"math"          // Pacote para funções matemáticas.
+	"strings"       // Pacote para montagem de strings.
+	"time"          // Pacote para manipulação de tempo e durações.
+
+	// Dependência interna do projeto.
+	"github.com/denner-s/gorpcstress/internal/metrics" // Histogramas de latência.
+)
+
+// Dimensões e margens comuns aos gráficos SVG.
+const (
+	chartWidth  = 760
+	chartHeight = 260
+	marginLeft  = 70
+	marginRight = 20
+	marginTop   = 20
+	marginBot   = 40
+)
+
+// Paleta usada pelas séries e fatias dos gráficos.
+var chartColors = []string{"#2563eb", "#f59e0b", "#dc2626", "#16a34a", "#7c3aed", "#0891b2", "#db2777", "#65a30d", "#6b7280"}
+
+// Estrutura chartSeries descreve uma série de um gráfico de linhas.
+type chartSeries struct {
+	Name   string
+	Color  string
+	Values []float64
+}
+
+// Função lineChart desenha um gráfico de linhas em SVG. xs contém a posição de cada
+// ponto no eixo horizontal (em segundos) e format rotula os valores do eixo vertical.
+func lineChart(xs []float64, series []chartSeries, format func(float64) string) template.HTML {
+	if len(xs) == 0 {
+		return emptyChart("Série temporal desabilitada ou vazia")
+	}
+
+	maxX := math.Max(xs[len(xs)-1], 1e-9)
+	var maxY float64
+	for _, s := range series {
+		for _, v := range s.Values {
+			maxY = math.Max(maxY, v)
+		}
+	}
+	if maxY == 0 {
+		maxY = 1
+	}
+
+	plotW := float64(chartWidth - marginLeft - marginRight)
+	plotH := float64(chartHeight - marginTop - marginBot)
+	x := func(v float64) float64 { return marginLeft + v/maxX*plotW }
+	y := func(v float64) float64 { return marginTop + plotH - v/maxY*plotH }
+
+	var b strings.Builder
+	openSVG(&b)
+	drawYAxis(&b, maxY, format)
+
+	// Rótulos do eixo horizontal em segundos.
+	for i := 0; i <= 5; i++ {
+		v := maxX * float64(i) / 5
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" class="axis" text-anchor="middle">%.0fs</text>`, x(v), chartHeight-marginBot+18, v)
+	}
+
+	for _, s := range series {
+		points := make([]string, 0, len(s.Values))
+		for i, v := range s.Values {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", x(xs[i]), y(v)))
+		}
+		fmt.Fprintf(&b, `<polyline fill="none" stroke="%s" stroke-width="2" points="%s"><title>%s</title></polyline>`,
+			s.Color, strings.Join(points, " "), template.HTMLEscapeString(s.Name))
+	}
+
+	drawLegend(&b, series)
+	b.WriteString("</svg>")
+	return template.HTML(b.String())
+}
+
+// Função histogramChart desenha a distribuição de latências em barras com faixas de
+// largura logarítmica, adequadas a distribuições de cauda longa.
+func histogramChart(h *metrics.Histogram) template.HTML {
+	if h == nil || h.Count() == 0 {
+		return emptyChart("Sem latências registradas")
+	}
+
+	const bins = 40
+	low := math.Max(float64(h.Min()), float64(time.Microsecond))
+	high := math.Max(float64(h.Max()), low*1.01)
+	ratio := math.Log(high / low)
+
+	counts := make([]int64, bins)
+	h.ForEach(func(value time.Duration, count int64) {
+		i := int(math.Log(math.Max(float64(value), low)/low) / ratio * bins)
+		counts[min(max(i, 0), bins-1)] += count
+	})
+
+	var peak int64
+	for _, c := range counts {
+		peak = max(peak, c)
+	}
+
+	plotW := float64(chartWidth - marginLeft - marginRight)
+	plotH := float64(chartHeight - marginTop - marginBot)
+	barW := plotW / bins
+	edge := func(i int) time.Duration { return time.Duration(low * math.Exp(ratio*float64(i)/bins)) }
+
+	var b strings.Builder
+	openSVG(&b)
+	drawYAxis(&b, float64(peak), func(v float64) string { return fmt.Sprintf("%.0f", v) })
+
+	for i, c := range counts {
+		height := float64(c) / float64(peak) * plotH
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%v – %v: %d</title></rect>`,
+			marginLeft+float64(i)*barW+1, marginTop+plotH-height, barW-2, height, chartColors[0],
+			edge(i).Round(time.Microsecond), edge(i+1).Round(time.Microsecond), c)
+	}
+	for i := 0; i <= bins; i += bins / 5 {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" class="axis" text-anchor="middle">%v</text>`,
+			marginLeft+float64(i)*barW, chartHeight-marginBot+18, edge(i).Round(time.Microsecond))
+	}
+
+	b.WriteString("</svg>")
+	return template.HTML(b.String())
+}
+
+// Função pieChart desenha a participação de cada mensagem de erro. As maiores fatias
+// são exibidas individualmente e as demais agrupadas.
+func pieChart(errs []ErrorCount) template.HTML {
+	if len(errs) == 0 {
+		return emptyChart("Nenhum erro registrado")
+	}
+
+	const maxSlices = 8
+	slices := errs
+	if len(errs) > maxSlices {
+		slices = append([]ErrorCount{}, errs[:maxSlices-1]...)
+		rest := ErrorCount{Message: metrics.OtherErrors}
+		for _, e := range errs[maxSlices-1:] {
+			rest.Count += e.Count
+		}
+		slices = append(slices, rest)
+	}
+
+	var total int
+	for _, e := range slices {
+		total += e.Count
+	}
+
+	const cx, cy, r = 130.0, 130.0, 110.0
+	var b strings.Builder
+	openSVG(&b)
+
+	angle := -math.Pi / 2
+	for i, e := range slices {
+		color := chartColors[i%len(chartColors)]
+		title := template.HTMLEscapeString(fmt.Sprintf("%s: %d", e.Message, e.Count))
+		share := float64(e.Count) / float64(total)
+
+		if share >= 1 {
+			fmt.Fprintf(&b, `<circle cx="%.0f" cy="%.0f" r="%.0f" fill="%s"><title>%s</title></circle>`, cx, cy, r, color, title)
+		} else {
+			end := angle + share*2*math.Pi
+			large := 0
+			if share > 0.5 {
+				large = 1
+			}
+			fmt.Fprintf(&b, `<path d="M%.1f,%.1f L%.1f,%.1f A%.0f,%.0f 0 %d 1 %.1f,%.1f Z" fill="%s"><title>%s</title></path>`,
+				cx, cy, cx+r*math.Cos(angle), cy+r*math.Sin(angle), r, r, large, cx+r*math.Cos(end), cy+r*math.Sin(end), color, title)
+			angle = end
+		}
+
+		// Legenda ao lado do gráfico, com a mensagem abreviada.
+		ly := 30 + i*24
+		fmt.Fprintf(&b, `<rect x="280" y="%d" width="12" height="12" fill="%s"/>`, ly-10, color)
+		fmt.Fprintf(&b, `<text x="300" y="%d" class="legend">%.1f%% — %s<title>%s</title></text>`,
+			ly, share*100, template.HTMLEscapeString(abbreviate(e.Message, 70)), title)
+	}
+
+	b.WriteString("</svg>")
+	return template.HTML(b.String())
+}
+
+// Função openSVG inicia um elemento SVG com as dimensões padrão.
+func openSVG(b *strings.Builder) {
+	fmt.Fprintf(b, `<svg viewBox="0 0 %d %d" role="img" xmlns="http://www.w3.org/2000/svg">`, chartWidth, chartHeight)
+}
+
+// Função drawYAxis desenha as linhas de grade e os rótulos do eixo vertical.
+func drawYAxis(b *strings.Builder, maxY float64, format func(float64) string) {
+	plotH := float64(chartHeight - marginTop - marginBot)
+	for i := 0; i <= 4; i++ {
+		v := maxY * float64(i) / 4
+		y := marginTop + plotH - plotH*float64(i)/4
+		fmt.Fprintf(b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" class="grid"/>`, marginLeft, y, chartWidth-marginRight, y)
+		fmt.Fprintf(b, `<text x="%d" y="%.1f" class="axis" text-anchor="end">%s</text>`, marginLeft-6, y+4, template.HTMLEscapeString(format(v)))
+	}
+}
+
+// Função drawLegend desenha a legenda das séries de um gráfico de linhas.
+func drawLegend(b *strings.Builder, series []chartSeries) {
+	for i, s := range series {
+		x := marginLeft + 10 + i*110
+		fmt.Fprintf(b, `<rect x="%d" y="%d" width="12" height="3" fill="%s"/>`, x, marginTop, s.Color)
+		fmt.Fprintf(b, `<text x="%d" y="%d" class="legend">%s</text>`, x+16, marginTop+5, template.HTMLEscapeString(s.Name))
+	}
+}
+
+// Função emptyChart substitui um gráfico sem dados por uma mensagem.
+func emptyChart(message string) template.HTML {
+	return template.HTML(`<p class="empty">` + template.HTMLEscapeString(message) + `</p>`)
+}
+
+// Função abbreviate limita o tamanho de um texto, indicando o corte com reticências.
+func abbreviate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,125 @@
+package report
+
+// Importação de pacotes necessários.
+import (
+	"fmt"            // Pacote para formatação de strings.
+	"io"             // Pacote para abstração de escrita.
+	"text/tabwriter" // Pacote para alinhamento de tabelas.
+	"time"           // Pacote para manipulação de tempo e durações.
+
+	// Dependências internas do projeto.
+	"github.com/denner-s/gorpcstress/internal/config"  // Critérios de aprovação.
+	"github.com/denner-s/gorpcstress/internal/metrics" // Métricas coletadas durante o teste.
+)
+
+// Estrutura ThresholdResult registra a avaliação de um critério de aprovação. Value
+// segue as unidades do relatório: latências em microssegundos e taxas em porcentagem.
+type ThresholdResult struct {
+	Expr   string  `json:"expr"`
+	Metric string  `json:"metric"`
+	Value  float64 `json:"value"`
+	Actual string  `json:"actual"`
+	Passed bool    `json:"passed"`
+}
+
+// Função EvaluateThresholds avalia os critérios de aprovação contra as métricas finais.
+// As latências são avaliadas sobre o tempo de resposta; sem nenhuma resposta medida,
+// os critérios de latência falham com o valor "sem dados".
+func EvaluateThresholds(thresholds []config.Threshold, m metrics.Metrics) []ThresholdResult {
+	results := make([]ThresholdResult, 0, len(thresholds))
+	for _, t := range thresholds {
+		if t.IsLatency() && m.ResponseTimes.Count() == 0 {
+			results = append(results, ThresholdResult{Expr: t.Expr, Metric: t.Metric, Actual: "sem dados"})
+			continue
+		}
+
+		actual := thresholdValue(t.Metric, m)
+		value := actual
+		if t.IsLatency() {
+			value = micros(time.Duration(actual))
+		}
+		results = append(results, ThresholdResult{
+			Expr:   t.Expr,
+			Metric: t.Metric,
+			Value:  value,
+			Actual: formatThresholdValue(t, actual),
+			Passed: t.Holds(actual),
+		})
+	}
+	return results
+}
+
+// Função ThresholdsPassed indica se todos os critérios foram satisfeitos.
+func ThresholdsPassed(results []ThresholdResult) bool {
+	for _, r := range results {
+		if !r.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Função thresholdValue extrai das métricas o valor avaliado por um critério.
+func thresholdValue(metric string, m metrics.Metrics) float64 {
+	switch metric {
+	case "rps":
+		return throughput(m)
+	case "error_rate":
+		return errorRate(m)
+	case "requests":
+		return float64(m.TotalRequests)
+	case "errors":
+		return float64(m.Errors)
+	case "invalid":
+		return float64(m.Invalid)
+	case "min":
+		return float64(m.ResponseTimes.Min())
+	case "mean":
+		return float64(m.ResponseTimes.Mean())
+	case "p50":
+		return float64(m.ResponseTimes.Percentile(0.5))
+	case "p90":
+		return float64(m.ResponseTimes.Percentile(0.9))
+	case "p99":
+		return float64(m.ResponseTimes.Percentile(0.99))
+	case "p999":
+		return float64(m.ResponseTimes.Percentile(0.999))
+	default:
+		return float64(m.ResponseTimes.Max())
+	}
+}
+
+// Função formatThresholdValue formata o valor medido conforme a unidade do critério.
+func formatThresholdValue(t config.Threshold, v float64) string {
+	switch {
+	case t.IsLatency():
+		return time.Duration(v).Round(time.Microsecond).String()
+	case t.Metric == "rps":
+		return fmt.Sprintf("%.2f req/s", v)
+	case t.Metric == "error_rate":
+		return fmt.Sprintf("%.2f%%", v)
+	default:
+		return fmt.Sprintf("%.0f", v)
+	}
+}
+
+// Função WriteThresholds escreve a tabela de aprovação dos critérios.
+func WriteThresholds(w io.Writer, results []ThresholdResult) {
+	fmt.Fprintln(w, "\n=== Critérios de Aprovação ===")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Critério\tMedido\tStatus")
+	for _, r := range results {
+		status := "OK"
+		if !r.Passed {
+			status = "FALHOU"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Expr, r.Actual, status)
+	}
+	_ = tw.Flush()
+
+	if ThresholdsPassed(results) {
+		fmt.Fprintln(w, "\nResultado: aprovado")
+	} else {
+		fmt.Fprintln(w, "\nResultado: REPROVADO")
+	}
+}
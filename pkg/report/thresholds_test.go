@@ -0,0 +1,43 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/denner-s/gorpcstress/internal/config"
+	"github.com/denner-s/gorpcstress/internal/metrics"
+)
+
+func TestEvaluateThresholds(t *testing.T) {
+	succeeded := metrics.NewHistogram(3, time.Minute)
+	for i := 1; i <= 100; i++ {
+		succeeded.Record(time.Duration(i) * time.Millisecond)
+	}
+
+	tests := []struct {
+		name      string
+		expr      string
+		responses *metrics.Histogram
+		errors    int
+		actual    string // Valor medido esperado; vazio não verifica
+		passed    bool
+	}{
+		{"latência satisfeita", "p50 < 60ms", succeeded, 0, "", true},
+		{"latência violada", "max < 50ms", succeeded, 0, "", false},
+		{"latência sem respostas", "p99 < 50ms", metrics.NewHistogram(3, time.Minute), 100, "sem dados", false},
+		{"contagem sem respostas", "errors <= 100", metrics.NewHistogram(3, time.Minute), 100, "100", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threshold, err := config.ParseThreshold(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m := metrics.Metrics{TotalRequests: 100, Errors: tt.errors, ResponseTimes: tt.responses}
+			r := EvaluateThresholds([]config.Threshold{threshold}, m)[0]
+			if (tt.actual != "" && r.Actual != tt.actual) || r.Passed != tt.passed {
+				t.Errorf("%s: medido %s, aprovado %v; esperado %s, %v", tt.expr, r.Actual, r.Passed, tt.actual, tt.passed)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package report
+
+// Importação de pacotes necessários.
+import (
+	"encoding/csv"   // Pacote para escrita de arquivos CSV.
+	"fmt"            // Pacote para formatação de strings.
+	"io"             // Pacote para abstração de escrita.
+	"strconv"        // Pacote para conversão de números.
+	"text/tabwriter" // Pacote para alinhamento de tabelas.
+	"time"           // Pacote para manipulação de tempo e durações.
+
+	// Dependência interna do projeto.
+	"github.com/denner-s/gorpcstress/internal/metrics" // Métricas coletadas durante o teste.
+)
+
+// Função printTimeSeries exibe a série temporal de throughput, latência e erros.
+func printTimeSeries(w io.Writer, m metrics.Metrics) {
+	if len(m.TimeSeries) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\nSérie temporal (intervalos de %v, latência = tempo de resposta):\n", m.Interval)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Início\tReq\tRPS\tErros\tDescartes\tp50\tp90\tp99\tMax\t")
+	for _, in := range m.TimeSeries {
+		fmt.Fprintf(tw, "%v\t%d\t%.1f\t%d\t%d\t%v\t%v\t%v\t%v\t\n",
+			in.Offset, in.Requests, intervalRate(in), in.Errors, in.Dropped,
+			in.P50.Round(time.Microsecond), in.P90.Round(time.Microsecond),
+			in.P99.Round(time.Microsecond), in.Max.Round(time.Microsecond))
+	}
+	_ = tw.Flush()
+}
+
+// Função intervalRate calcula a taxa de requisições concluídas por segundo em um intervalo.
+func intervalRate(in metrics.IntervalMetrics) float64 {
+	if in.Length <= 0 {
+		return 0
+	}
+	return float64(in.Requests) / in.Length.Seconds()
+}
+
+// Função WriteTimeSeriesCSV grava a série temporal em formato CSV, com latências em
+// microssegundos, para consumo por planilhas e outras ferramentas.
+func WriteTimeSeriesCSV(w io.Writer, m metrics.Metrics) error {
+	out := csv.NewWriter(w)
+	header := []string{"offset_s", "requests", "rps", "errors", "dropped", "p50_us", "p90_us", "p99_us", "max_us"}
+	if err := out.Write(header); err != nil {
+		return err
+	}
+
+	for _, in := range m.TimeSeries {
+		record := []string{
+			strconv.FormatFloat(in.Offset.Seconds(), 'f', -1, 64),
+			strconv.Itoa(in.Requests),
+			strconv.FormatFloat(intervalRate(in), 'f', 2, 64),
+			strconv.Itoa(in.Errors),
+			strconv.Itoa(in.Dropped),
+			strconv.FormatInt(in.P50.Microseconds(), 10),
+			strconv.FormatInt(in.P90.Microseconds(), 10),
+			strconv.FormatInt(in.P99.Microseconds(), 10),
+			strconv.FormatInt(in.Max.Microseconds(), 10),
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
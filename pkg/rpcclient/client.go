@@ -1,12 +1,52 @@
 package rpcclient
 
 import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"net/rpc"
+	"net/rpc/jsonrpc"
+	"reflect"
+	"sync/atomic"
 	"time"
 )
 
+// Codecs suportados para a troca de mensagens com o servidor.
+const (
+	CodecGob     = "gob"     // Codificação padrão do net/rpc
+	CodecJSONRPC = "jsonrpc" // JSON-RPC 1.0 do net/rpc/jsonrpc
+)
+
+// ErrTimeout indica uma chamada abandonada pelo cliente ao fim do prazo de Options.Timeout,
+// ainda em voo no servidor.
+var ErrTimeout = errors.New("timeout")
+
+// connectedStatus é a resposta do net/rpc a um handshake HTTP CONNECT bem-sucedido.
+const connectedStatus = "200 Connected to Go RPC"
+
+// Options define como o cliente se conecta e se comunica com o servidor.
+type Options struct {
+	Timeout time.Duration // Tempo máximo para conexão/chamadas
+	Codec   string        // Codec das mensagens: CodecGob (padrão) ou CodecJSONRPC
+
+	// Configuração TLS das conexões; nil usa conexões sem criptografia. Sem ServerName,
+	// o host do endereço é verificado no certificado do servidor
+	TLS *tls.Config
+
+	// Caminho do handshake HTTP CONNECT para servidores montados com rpc.HandleHTTP
+	// (ex: rpc.DefaultRPCPath); vazio usa TCP puro
+	HTTPPath string
+
+	// Opções do transporte JSON-RPC 2.0 sobre HTTP
+	Headers   http.Header // Cabeçalhos enviados em cada requisição
+	BatchSize int         // Requisições por lote (1 desativa os lotes)
+	MaxConns  int         // Chamadas simultâneas esperadas no cliente; conexões keep-alive mantidas (padrão 1)
+}
+
 // Args define os parâmetros de entrada para operações RPC.
 // Campos exportados (maiúsculos) garantem serialização correta.
 type Args struct {
@@ -21,33 +61,169 @@ type Reply struct {
 // Client encapsula uma conexão RPC com timeout.
 type Client struct {
 	*rpc.Client
-	Timeout time.Duration // Tempo máximo para conexão/chamadas
+	Timeout       time.Duration // Tempo máximo para conexão/chamadas
+	DialTime      time.Duration // Tempo gasto no estabelecimento da conexão TCP ou Unix
+	TLSTime       time.Duration // Tempo gasto no handshake TLS (zero sem Options.TLS)
+	HandshakeTime time.Duration // Tempo gasto no handshake HTTP CONNECT (zero sem Options.HTTPPath)
+
+	reported atomic.Bool // Indica que os tempos da conexão já foram devolvidos por TakeConnStats
 }
 
 // NewClient estabelece uma conexão com o servidor RPC.
-// - `serverAddress`: Endereço no formato "host:porta"
-// - `timeout`: Tempo máximo de espera por conexão
-func NewClient(serverAddress string, timeout time.Duration) (*Client, error) {
-	conn, err := net.DialTimeout("tcp", serverAddress, timeout)
+// - `ctx`: Cancela a tentativa de conexão (ex: interrupção do teste)
+// - `target`: Rede e endereço do servidor (veja ParseTarget)
+// - `opts`: Timeout de conexão/chamadas, codec das mensagens, TLS e handshake HTTP
+func NewClient(ctx context.Context, target Target, opts Options) (*Client, error) {
+	start := time.Now()
+	dialer := net.Dialer{Timeout: opts.Timeout}
+	conn, err := dialer.DialContext(ctx, target.Network, target.Address)
 	if err != nil {
 		return nil, fmt.Errorf("falha na conexão: %w", err) // Erro detalhado
 	}
+	dialTime := time.Since(start)
+
+	// O handshake TLS é medido separadamente da conexão TCP
+	var tlsTime time.Duration
+	if opts.TLS != nil {
+		start = time.Now()
+		if conn, err = tlsHandshake(ctx, conn, target.Address, opts); err != nil {
+			return nil, err
+		}
+		tlsTime = time.Since(start)
+	}
+
+	// Servidores montados com rpc.HandleHTTP exigem o handshake antes das chamadas
+	var handshakeTime time.Duration
+	if opts.HTTPPath != "" {
+		start = time.Now()
+		if err := httpConnect(ctx, conn, opts.HTTPPath, opts.Timeout); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		handshakeTime = time.Since(start)
+	}
+
+	// Seleciona o codec; gob é o padrão do net/rpc
+	var client *rpc.Client
+	switch opts.Codec {
+	case CodecJSONRPC:
+		client = jsonrpc.NewClient(conn)
+	case CodecGob, "":
+		client = rpc.NewClient(conn)
+	default:
+		_ = conn.Close()
+		return nil, fmt.Errorf("codec desconhecido %q", opts.Codec)
+	}
+
 	return &Client{
-		Client:  rpc.NewClient(conn),
-		Timeout: timeout,
+		Client:        client,
+		Timeout:       opts.Timeout,
+		DialTime:      dialTime,
+		TLSTime:       tlsTime,
+		HandshakeTime: handshakeTime,
 	}, nil
 }
 
-// Call executa uma chamada RPC com controle de timeout.
-// - Usa goroutine + channel para evitar bloqueio indefinido
-func (c *Client) Call(serviceMethod string, args interface{}, reply interface{}) error {
-	done := make(chan error, 1)
-	go func() { done <- c.Client.Call(serviceMethod, args, reply) }()
+// tlsHandshake estabelece a sessão TLS sobre a conexão TCP; em caso de falha a conexão
+// é fechada.
+func tlsHandshake(ctx context.Context, conn net.Conn, serverAddress string, opts Options) (net.Conn, error) {
+	config := opts.TLS
+	if config.ServerName == "" {
+		host, _, err := net.SplitHostPort(serverAddress)
+		if err != nil {
+			host = serverAddress
+		}
+		config = config.Clone()
+		config.ServerName = host
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("falha no handshake TLS: %w", err)
+	}
+	return tlsConn, nil
+}
+
+// httpConnect executa o handshake HTTP CONNECT do net/rpc (o mesmo de rpc.DialHTTPPath),
+// respeitando o timeout e o cancelamento de ctx.
+func httpConnect(ctx context.Context, conn net.Conn, path string, timeout time.Duration) error {
+	if timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(timeout))
+		defer func() { _ = conn.SetDeadline(time.Time{}) }()
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	if _, err := fmt.Fprintf(conn, "CONNECT %s HTTP/1.0\n\n", path); err != nil {
+		return fmt.Errorf("falha no handshake HTTP: %w", err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), &http.Request{Method: http.MethodConnect})
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("falha no handshake HTTP: %w", err)
+	}
+	if resp.Status != connectedStatus {
+		return fmt.Errorf("falha no handshake HTTP: resposta inesperada %q em %s", resp.Status, path)
+	}
+	return nil
+}
+
+// Call executa uma chamada RPC com controle de timeout e cancelamento.
+// - Envia com rpc.Client.Go e aguarda o canal de conclusão, sem goroutines extras
+// - Aplica o prazo de Timeout sobre o contexto recebido
+// - Retorna um erro ErrTimeout ao fim do prazo e ctx.Err() se o contexto for cancelado
+//
+// A resposta é decodificada em um valor próprio da chamada e copiada para reply apenas
+// em caso de sucesso: uma chamada abandonada continua em voo até a resposta chegar ou a
+// conexão ser fechada, sem nunca escrever em reply.
+func (c *Client) Call(ctx context.Context, serviceMethod string, args interface{}, reply interface{}) error {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	private := newReply(reply)
+	call := c.Client.Go(serviceMethod, args, private, make(chan *rpc.Call, 1))
 
 	select {
-	case err := <-done:
-		return err // Retorna erro imediatamente se houver
-	case <-time.After(c.Timeout):
-		return fmt.Errorf("timeout após %v", c.Timeout) // Erro customizado
+	case <-call.Done:
+		if call.Error == nil && private != reply {
+			reflect.ValueOf(reply).Elem().Set(reflect.ValueOf(private).Elem())
+		}
+		return call.Error
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w após %v", ErrTimeout, c.Timeout)
+		}
+		return ctx.Err() // Chamada abandonada pelo chamador
+	}
+}
+
+// TakeConnStats devolve os tempos da conexão do cliente na primeira consulta; o
+// cliente não abre outras conexões.
+func (c *Client) TakeConnStats() []ConnStats {
+	if c.reported.Swap(true) {
+		return nil
+	}
+	return []ConnStats{{Dial: c.DialTime, TLS: c.TLSTime, Handshake: c.HandshakeTime}}
+}
+
+// newReply cria um valor do mesmo tipo apontado por reply, onde a resposta da chamada
+// é decodificada. Respostas que não são ponteiros são usadas diretamente.
+func newReply(reply interface{}) interface{} {
+	v := reflect.ValueOf(reply)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return reply
 	}
+	return reflect.New(v.Type().Elem()).Interface()
 }
@@ -0,0 +1,209 @@
+package rpcclient
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"strings"
+	"testing"
+	"time"
+)
+
+// arithmetic é o serviço do servidor de exemplo, com um atraso opcional.
+type arithmetic struct {
+	delay time.Duration
+}
+
+// Multiply multiplica os operandos após o atraso configurado.
+func (a *arithmetic) Multiply(args *Args, reply *Reply) error {
+	time.Sleep(a.delay)
+	reply.Result = args.A * args.B
+	return nil
+}
+
+// startServer inicia um servidor em processo com o codec informado e devolve seu endereço.
+func startServer(t *testing.T, codec string, delay time.Duration) Target {
+	t.Helper()
+	server := rpc.NewServer()
+	if err := server.RegisterName("Arithmetic", &arithmetic{delay: delay}); err != nil {
+		t.Fatal(err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			if codec == CodecJSONRPC {
+				go server.ServeCodec(jsonrpc.NewServerCodec(conn))
+			} else {
+				go server.ServeConn(conn)
+			}
+		}
+	}()
+	return Target{Network: NetworkTCP, Address: listener.Addr().String()}
+}
+
+func TestClientCodecs(t *testing.T) {
+	for _, codec := range []string{"", CodecGob, CodecJSONRPC} {
+		addr := startServer(t, codec, 0)
+		client, err := NewClient(context.Background(), addr, Options{Timeout: time.Second, Codec: codec})
+		if err != nil {
+			t.Fatalf("codec %q: %v", codec, err)
+		}
+
+		var reply Reply
+		if err := client.Call(context.Background(), "Arithmetic.Multiply", &Args{A: 6, B: 7}, &reply); err != nil || reply.Result != 42 {
+			t.Errorf("codec %q: resultado %d, erro %v; esperado 42", codec, reply.Result, err)
+		}
+		_ = client.Close()
+	}
+
+	if _, err := NewClient(context.Background(), startServer(t, CodecGob, 0), Options{Codec: "xml"}); err == nil || !strings.Contains(err.Error(), "codec desconhecido") {
+		t.Errorf("codec desconhecido aceito: %v", err)
+	}
+}
+
+func TestClientCallTimeout(t *testing.T) {
+	addr := startServer(t, CodecGob, 100*time.Millisecond)
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		err     error
+		result  int
+	}{
+		{"resposta após o prazo", 30 * time.Millisecond, ErrTimeout, -1},
+		{"resposta no prazo", time.Second, nil, 42},
+		{"sem timeout", 0, nil, 42},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(context.Background(), addr, Options{Timeout: tt.timeout})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = client.Close() }()
+
+			reply := Reply{Result: -1}
+			if err := client.Call(context.Background(), "Arithmetic.Multiply", &Args{A: 6, B: 7}, &reply); !errors.Is(err, tt.err) {
+				t.Fatalf("chamada retornou %v, esperado %v", err, tt.err)
+			}
+
+			// A resposta de uma chamada abandonada chega depois, sem escrever em reply.
+			time.Sleep(150 * time.Millisecond)
+			if reply.Result != tt.result {
+				t.Errorf("resultado %d, esperado %d", reply.Result, tt.result)
+			}
+		})
+	}
+}
+
+func TestClientCallInterrupted(t *testing.T) {
+	addr := startServer(t, CodecGob, 200*time.Millisecond)
+	client, err := NewClient(context.Background(), addr, Options{Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }()
+	var reply Reply
+
+	// O cancelamento do contexto abandona a chamada antes do timeout.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := client.Call(ctx, "Arithmetic.Multiply", &Args{A: 6, B: 7}, &reply); !errors.Is(err, context.Canceled) {
+		t.Errorf("chamada cancelada retornou %v", err)
+	}
+}
+
+func TestClientHTTPConnect(t *testing.T) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("Arithmetic", &arithmetic{}); err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/rpc", server)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	addr := Target{Network: NetworkTCP, Address: strings.TrimPrefix(srv.URL, "http://")}
+
+	client, err := NewClient(context.Background(), addr, Options{Timeout: time.Second, HTTPPath: "/rpc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }()
+
+	var reply Reply
+	if err := client.Call(context.Background(), "Arithmetic.Multiply", &Args{A: 6, B: 7}, &reply); err != nil || reply.Result != 42 {
+		t.Errorf("resultado %d, erro %v; esperado 42", reply.Result, err)
+	}
+
+	// Os tempos da conexão e do handshake são devolvidos uma única vez.
+	conns := client.TakeConnStats()
+	if len(conns) != 1 || conns[0].Dial <= 0 || conns[0].Handshake <= 0 || conns[0].Err != nil {
+		t.Errorf("conexões registradas %+v", conns)
+	}
+	if len(client.TakeConnStats()) != 0 {
+		t.Error("conexão devolvida em duas consultas")
+	}
+
+	if _, err := NewClient(context.Background(), addr, Options{Timeout: time.Second, HTTPPath: "/outro"}); err == nil || !strings.Contains(err.Error(), "handshake HTTP") {
+		t.Errorf("handshake em caminho inexistente retornou %v", err)
+	}
+}
+
+// testTLS devolve as configurações TLS do servidor e do cliente com o certificado de
+// teste do httptest, válido para 127.0.0.1.
+func testTLS(t *testing.T) (server, client *tls.Config) {
+	t.Helper()
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	server = &tls.Config{Certificates: srv.TLS.Certificates}
+	client = &tls.Config{RootCAs: srv.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs}
+	return server, client
+}
+
+func TestClientTLS(t *testing.T) {
+	serverTLS, clientTLS := testTLS(t)
+	server := rpc.NewServer()
+	if err := server.RegisterName("Arithmetic", &arithmetic{}); err != nil {
+		t.Fatal(err)
+	}
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverTLS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = listener.Close() }()
+	go server.Accept(listener)
+	target := Target{Network: NetworkTCP, Address: listener.Addr().String()}
+
+	client, err := NewClient(context.Background(), target, Options{Timeout: time.Second, TLS: clientTLS})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }()
+
+	var reply Reply
+	if err := client.Call(context.Background(), "Arithmetic.Multiply", &Args{A: 6, B: 7}, &reply); err != nil || reply.Result != 42 {
+		t.Errorf("resultado %d, erro %v; esperado 42", reply.Result, err)
+	}
+	if conns := client.TakeConnStats(); len(conns) != 1 || conns[0].TLS <= 0 {
+		t.Errorf("handshake TLS não medido: %+v", conns)
+	}
+
+	// Sem a CA do servidor, o certificado não é aceito.
+	_, err = NewClient(context.Background(), target, Options{Timeout: time.Second, TLS: &tls.Config{}})
+	if err == nil || !strings.Contains(err.Error(), "handshake TLS") {
+		t.Errorf("certificado não confiável retornou %v", err)
+	}
+}
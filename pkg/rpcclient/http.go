@@ -0,0 +1,306 @@
+package rpcclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxErrorBody limita quanto do corpo de uma resposta HTTP de erro é lido para a mensagem.
+const maxErrorBody = 512
+
+// Caller é a interface comum aos transportes do cliente: net/rpc sobre TCP ou socket
+// Unix (Client) e JSON-RPC 2.0 sobre HTTP (HTTPClient).
+type Caller interface {
+	// Call executa uma chamada; retorna ctx.Err() se o contexto for cancelado antes da resposta.
+	Call(ctx context.Context, serviceMethod string, args interface{}, reply interface{}) error
+	// Close libera as conexões do cliente.
+	Close() error
+	// TakeConnStats devolve as conexões abertas pelo cliente desde a consulta anterior.
+	TakeConnStats() []ConnStats
+}
+
+// ConnStats descreve uma tentativa de conexão do cliente com o servidor.
+type ConnStats struct {
+	Dial      time.Duration // Tempo de estabelecimento da conexão TCP.
+	TLS       time.Duration // Tempo do handshake TLS (zero sem TLS).
+	Handshake time.Duration // Tempo do handshake HTTP CONNECT (zero sem handshake).
+	Err       error         // Erro (se houver) ao conectar.
+	Reconnect bool          // Indica que a conexão substitui uma conexão anterior do cliente.
+}
+
+// IsHTTP indica se o endereço do servidor usa o transporte JSON-RPC 2.0 sobre HTTP.
+func IsHTTP(serverAddress string) bool {
+	return strings.HasPrefix(serverAddress, "http://") || strings.HasPrefix(serverAddress, "https://")
+}
+
+// Dial cria um cliente para o endereço informado: URLs http:// e https:// usam
+// JSON-RPC 2.0 sobre HTTP e os demais endereços (veja ParseTarget), net/rpc sobre
+// TCP ou socket Unix.
+func Dial(ctx context.Context, serverAddress string, opts Options) (Caller, error) {
+	if IsHTTP(serverAddress) {
+		return NewHTTPClient(serverAddress, opts), nil
+	}
+	target, err := ParseTarget(serverAddress)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(ctx, target, opts)
+}
+
+// HTTPClient envia chamadas JSON-RPC 2.0 via HTTP POST. Cada cliente mantém suas
+// próprias conexões keep-alive, reutilizadas entre as chamadas; chamadas simultâneas
+// no mesmo cliente usam conexões distintas.
+type HTTPClient struct {
+	url     string
+	client  *http.Client
+	headers http.Header
+	batch   int           // Número de requisições enviadas em cada lote
+	timeout time.Duration // Tempo máximo de cada chamada
+	nextID  atomic.Uint64
+
+	// Conexões são abertas pelo transporte durante as chamadas; seus tempos ficam
+	// guardados até a consulta de TakeConnStats
+	mu     sync.Mutex
+	conns  []ConnStats
+	dialed bool
+}
+
+// NewHTTPClient cria um cliente JSON-RPC 2.0 para a URL informada.
+func NewHTTPClient(url string, opts Options) *HTTPClient {
+	c := &HTTPClient{
+		url:     url,
+		headers: opts.Headers,
+		batch:   max(opts.BatchSize, 1),
+		timeout: opts.Timeout,
+	}
+	dialer := &net.Dialer{Timeout: opts.Timeout, KeepAlive: 30 * time.Second}
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			start := time.Now()
+			conn, err := dialer.DialContext(ctx, network, addr)
+			c.recordConn(ConnStats{Dial: time.Since(start), Err: err})
+			return conn, err
+		},
+		// O handshake TLS é medido separadamente da conexão TCP, como em NewClient
+		DialTLSContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			start := time.Now()
+			conn, err := dialer.DialContext(ctx, network, addr)
+			stats := ConnStats{Dial: time.Since(start), Err: err}
+			if err == nil {
+				tlsOpts := opts
+				if tlsOpts.TLS == nil {
+					tlsOpts.TLS = &tls.Config{}
+				}
+				start = time.Now()
+				conn, err = tlsHandshake(ctx, conn, addr, tlsOpts)
+				stats.TLS, stats.Err = time.Since(start), err
+			}
+			c.recordConn(stats)
+			return conn, err
+		},
+		TLSClientConfig:     opts.TLS,
+		MaxIdleConnsPerHost: max(opts.MaxConns, 1), // Mantém uma conexão por chamada simultânea
+		IdleConnTimeout:     90 * time.Second,
+	}
+	c.client = &http.Client{Transport: transport}
+	return c
+}
+
+// recordConn guarda os tempos de uma tentativa de conexão do transporte.
+func (c *HTTPClient) recordConn(stats ConnStats) {
+	if errors.Is(stats.Err, context.Canceled) {
+		return // Conexão cancelada pela interrupção do teste não é uma falha
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats.Reconnect = c.dialed
+	c.dialed = c.dialed || stats.Err == nil
+	c.conns = append(c.conns, stats)
+}
+
+// TakeConnStats devolve as tentativas de conexão feitas pelo transporte desde a
+// consulta anterior.
+func (c *HTTPClient) TakeConnStats() []ConnStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	conns := c.conns
+	c.conns = nil
+	return conns
+}
+
+// Estrutura rpcRequest é o envelope de uma requisição JSON-RPC 2.0.
+type rpcRequest struct {
+	Version string      `json:"jsonrpc"`
+	ID      uint64      `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// Estrutura rpcResponse é o envelope de uma resposta JSON-RPC 2.0.
+type rpcResponse struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// RPCError é um objeto de erro JSON-RPC 2.0 devolvido pelo servidor.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Error formata o erro com a categoria definida pela especificação para o código.
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("JSON-RPC %d (%s): %s", e.Code, e.Category(), e.Message)
+}
+
+// Category classifica o código de erro conforme a especificação JSON-RPC 2.0.
+func (e *RPCError) Category() string {
+	switch {
+	case e.Code == -32700:
+		return "erro de parse"
+	case e.Code == -32600:
+		return "requisição inválida"
+	case e.Code == -32601:
+		return "método não encontrado"
+	case e.Code == -32602:
+		return "parâmetros inválidos"
+	case e.Code == -32603:
+		return "erro interno"
+	case e.Code <= -32000 && e.Code >= -32099:
+		return "erro do servidor"
+	default:
+		return "erro da aplicação"
+	}
+}
+
+// Call envia a chamada (ou um lote de chamadas idênticas, conforme Options.BatchSize)
+// e decodifica o resultado em reply. Em lotes, reply recebe o resultado da primeira
+// resposta e o primeiro erro encontrado é devolvido: o lote inteiro é uma única
+// chamada, medida e contabilizada como uma requisição.
+func (c *HTTPClient) Call(ctx context.Context, serviceMethod string, args interface{}, reply interface{}) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	requests := make([]rpcRequest, c.batch)
+	for i := range requests {
+		requests[i] = rpcRequest{Version: "2.0", ID: c.nextID.Add(1), Method: serviceMethod, Params: args}
+	}
+
+	var body []byte
+	var err error
+	if c.batch == 1 {
+		body, err = json.Marshal(requests[0])
+	} else {
+		body, err = json.Marshal(requests)
+	}
+	if err != nil {
+		return fmt.Errorf("falha ao codificar requisição: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, values := range c.headers {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if c.timeout > 0 && errors.Is(err, context.DeadlineExceeded) && ctx.Err() != nil {
+			return fmt.Errorf("%w após %v", ErrTimeout, c.timeout)
+		}
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	// Respostas de erro HTTP sem objeto de erro JSON-RPC são reportadas pelo status.
+	responses, err := decodeResponses(data)
+	if resp.StatusCode >= 300 && !hasRPCError(responses) {
+		return fmt.Errorf("HTTP %s: %s", resp.Status, strings.TrimSpace(string(data[:min(len(data), maxErrorBody)])))
+	}
+	if err != nil {
+		return err
+	}
+
+	// O resultado do primeiro envelope do lote é decodificado na resposta.
+	byID := make(map[uint64]rpcResponse, len(responses))
+	for _, r := range responses {
+		if r.Error != nil && r.ID == 0 {
+			return r.Error // Erro sem id (ex: lote inválido) vale para todo o lote
+		}
+		byID[r.ID] = r
+	}
+	for i, request := range requests {
+		r, ok := byID[request.ID]
+		if !ok {
+			return fmt.Errorf("resposta JSON-RPC ausente para o id %d", request.ID)
+		}
+		if r.Error != nil {
+			return r.Error
+		}
+		if i == 0 && reply != nil {
+			if err := json.Unmarshal(r.Result, reply); err != nil {
+				return fmt.Errorf("falha ao decodificar resultado: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// decodeResponses interpreta o corpo da resposta como um envelope ou um lote de envelopes.
+func decodeResponses(data []byte) ([]rpcResponse, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var responses []rpcResponse
+		if err := json.Unmarshal(data, &responses); err != nil {
+			return nil, fmt.Errorf("resposta JSON-RPC inválida: %w", err)
+		}
+		return responses, nil
+	}
+
+	var response rpcResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("resposta JSON-RPC inválida: %w", err)
+	}
+	return []rpcResponse{response}, nil
+}
+
+// hasRPCError indica se alguma das respostas traz um objeto de erro JSON-RPC.
+func hasRPCError(responses []rpcResponse) bool {
+	for _, r := range responses {
+		if r.Error != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Close encerra as conexões keep-alive ociosas do cliente.
+func (c *HTTPClient) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,234 @@
+package rpcclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// jsonRPCServer é um servidor JSON-RPC 2.0 em processo que multiplica os operandos.
+// O método "Falha" devolve um erro JSON-RPC e o método "Lento" responde após delay.
+type jsonRPCServer struct {
+	*httptest.Server
+	conns   atomic.Int64  // Conexões TCP aceitas
+	batches chan int      // Tamanho de cada lote recebido
+	header  atomic.Value  // Último cabeçalho Authorization recebido
+	delay   time.Duration // Atraso do método "Lento"
+}
+
+// newJSONRPCServer inicia o servidor, encerrado ao fim do teste.
+func newJSONRPCServer(t *testing.T) *jsonRPCServer {
+	t.Helper()
+	s := &jsonRPCServer{batches: make(chan int, 100), delay: 200 * time.Millisecond}
+	s.Server = httptest.NewUnstartedServer(http.HandlerFunc(s.serve))
+	s.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			s.conns.Add(1)
+		}
+	}
+	s.Start()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// serve responde a uma requisição ou a um lote de requisições.
+func (s *jsonRPCServer) serve(w http.ResponseWriter, r *http.Request) {
+	s.header.Store(r.Header.Get("Authorization"))
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "corpo inválido", http.StatusBadRequest)
+		return
+	}
+
+	batch := bytes.HasPrefix(bytes.TrimSpace(body), []byte("["))
+	var requests []struct {
+		ID     uint64 `json:"id"`
+		Method string `json:"method"`
+		Params Args   `json:"params"`
+	}
+	if !batch {
+		body = append(append(json.RawMessage("["), body...), ']')
+	}
+	_ = json.Unmarshal(body, &requests)
+	s.batches <- len(requests)
+
+	responses := make([]map[string]interface{}, len(requests))
+	for i, req := range requests {
+		responses[i] = map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "Falha":
+			responses[i]["error"] = map[string]interface{}{"code": -32601, "message": "método desconhecido"}
+		case "Lento":
+			time.Sleep(s.delay)
+			fallthrough
+		default:
+			responses[i]["result"] = Reply{Result: req.Params.A * req.Params.B}
+		}
+	}
+	if batch {
+		_ = json.NewEncoder(w).Encode(responses)
+	} else {
+		_ = json.NewEncoder(w).Encode(responses[0])
+	}
+}
+
+func TestHTTPClientCall(t *testing.T) {
+	srv := newJSONRPCServer(t)
+	client, err := Dial(context.Background(), srv.URL, Options{Timeout: time.Second, Headers: http.Header{"Authorization": {"Bearer x"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }()
+
+	var reply Reply
+	if err := client.Call(context.Background(), "Arithmetic.Multiply", Args{A: 6, B: 7}, &reply); err != nil || reply.Result != 42 {
+		t.Fatalf("resultado %d, erro %v; esperado 42", reply.Result, err)
+	}
+	if got := srv.header.Load(); got != "Bearer x" {
+		t.Errorf("cabeçalho Authorization %q, esperado %q", got, "Bearer x")
+	}
+
+	err = client.Call(context.Background(), "Falha", Args{}, &reply)
+	if err == nil || !strings.Contains(err.Error(), "JSON-RPC -32601 (método não encontrado)") {
+		t.Errorf("erro JSON-RPC %v", err)
+	}
+}
+
+func TestHTTPClientBatch(t *testing.T) {
+	srv := newJSONRPCServer(t)
+	client := NewHTTPClient(srv.URL, Options{Timeout: time.Second, BatchSize: 3})
+	defer func() { _ = client.Close() }()
+
+	// O lote inteiro é enviado em uma única requisição HTTP e medido como uma chamada.
+	var reply Reply
+	if err := client.Call(context.Background(), "Arithmetic.Multiply", Args{A: 2, B: 3}, &reply); err != nil || reply.Result != 6 {
+		t.Fatalf("resultado %d, erro %v; esperado 6", reply.Result, err)
+	}
+	if size := <-srv.batches; size != 3 {
+		t.Errorf("lote com %d requisições, esperadas 3", size)
+	}
+	if err := client.Call(context.Background(), "Falha", Args{}, &reply); err == nil {
+		t.Error("lote com elementos falhos deveria falhar")
+	}
+}
+
+func TestHTTPClientStatusError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "indisponível", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	err := NewHTTPClient(srv.URL, Options{Timeout: time.Second}).Call(context.Background(), "Arithmetic.Multiply", Args{}, &Reply{})
+	if err == nil || !strings.Contains(err.Error(), "HTTP 503") || !strings.Contains(err.Error(), "indisponível") {
+		t.Errorf("resposta HTTP sem envelope retornou %v", err)
+	}
+}
+
+func TestHTTPClientTimeout(t *testing.T) {
+	srv := newJSONRPCServer(t)
+
+	err := NewHTTPClient(srv.URL, Options{Timeout: 50 * time.Millisecond}).Call(context.Background(), "Lento", Args{}, &Reply{})
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("chamada lenta retornou %v, esperado timeout", err)
+	}
+}
+
+func TestHTTPClientWithoutTimeout(t *testing.T) {
+	srv := newJSONRPCServer(t)
+	srv.delay = 20 * time.Millisecond
+
+	var reply Reply
+	if err := NewHTTPClient(srv.URL, Options{Timeout: 0}).Call(context.Background(), "Lento", Args{A: 1, B: 1}, &reply); err != nil || reply.Result != 1 {
+		t.Errorf("chamada sem timeout: resultado %d, erro %v", reply.Result, err)
+	}
+}
+
+func TestHTTPClientKeepsConnectionsForConcurrentCalls(t *testing.T) {
+	srv := newJSONRPCServer(t)
+	srv.delay = 20 * time.Millisecond
+	const workers = 4
+	client := NewHTTPClient(srv.URL, Options{Timeout: time.Second, MaxConns: workers})
+	defer func() { _ = client.Close() }()
+
+	// Workers que compartilham o cliente fazem chamadas simultâneas em rodadas; as
+	// conexões de uma rodada devem ser mantidas para as seguintes em vez de reabertas.
+	for round := 0; round < 3; round++ {
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := client.Call(context.Background(), "Lento", Args{A: 1, B: 1}, &Reply{}); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+
+	if conns := srv.conns.Load(); conns > workers {
+		t.Errorf("%d conexões abertas para %d workers", conns, workers)
+	}
+}
+
+func TestHTTPClientConnStats(t *testing.T) {
+	srv := newJSONRPCServer(t)
+	client := NewHTTPClient(srv.URL, Options{Timeout: time.Second})
+	defer func() { _ = client.Close() }()
+
+	for i := 0; i < 3; i++ {
+		if err := client.Call(context.Background(), "Arithmetic.Multiply", Args{A: 6, B: 7}, &Reply{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A conexão keep-alive é aberta na primeira chamada e reutilizada nas demais
+	conns := client.TakeConnStats()
+	if len(conns) != 1 || conns[0].Err != nil || conns[0].Dial <= 0 {
+		t.Fatalf("conexões registradas %+v, esperada 1", conns)
+	}
+	if len(client.TakeConnStats()) != 0 {
+		t.Error("conexões devolvidas em duas consultas")
+	}
+}
+
+func TestHTTPClientConnFailure(t *testing.T) {
+	srv := newJSONRPCServer(t)
+	srv.Close()
+
+	client := NewHTTPClient(srv.URL, Options{Timeout: time.Second})
+	if err := client.Call(context.Background(), "Arithmetic.Multiply", Args{}, &Reply{}); err == nil {
+		t.Fatal("chamada a servidor encerrado deveria falhar")
+	}
+	if conns := client.TakeConnStats(); len(conns) == 0 || conns[0].Err == nil {
+		t.Errorf("falha de conexão não registrada: %+v", conns)
+	}
+}
+
+func TestHTTPClientTLSConnStats(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": Reply{Result: 42}})
+	}))
+	defer srv.Close()
+
+	client := NewHTTPClient(srv.URL, Options{Timeout: time.Second, TLS: srv.Client().Transport.(*http.Transport).TLSClientConfig})
+	defer func() { _ = client.Close() }()
+	var reply Reply
+	if err := client.Call(context.Background(), "Arithmetic.Multiply", Args{}, &reply); err != nil || reply.Result != 42 {
+		t.Fatalf("resultado %d, erro %v", reply.Result, err)
+	}
+
+	// O handshake TLS é medido à parte da conexão TCP
+	if conns := client.TakeConnStats(); len(conns) != 1 || conns[0].Dial <= 0 || conns[0].TLS <= 0 || conns[0].Err != nil {
+		t.Errorf("conexões registradas %+v", conns)
+	}
+}
@@ -0,0 +1,444 @@
+package rpcclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MapKey marca, no arquivo de payload, um objeto JSON que deve ser enviado como mapa
+// (map[string]T) em vez de struct: {"$map": {"chave": "valor"}}.
+const MapKey = "$map"
+
+// Payload descreve os argumentos enviados em cada chamada e, opcionalmente, o formato
+// e o valor esperado da resposta.
+//
+// Os tipos são gerados em tempo de execução a partir do JSON para permanecerem
+// compatíveis com a codificação gob do net/rpc, que associa campos pelo nome:
+//   - objetos viram structs com os nomes dos campos iniciando em maiúscula
+//   - objetos marcados com "$map" viram map[string]T
+//   - arrays viram slices do tipo dos elementos
+//   - números inteiros viram int64 e números com ponto ou expoente, float64
+//   - strings e booleanos mantêm seus tipos
+//
+// Strings dos argumentos podem conter geradores entre chaves duplas (ex: "{{seq}}",
+// "user-{{randString 8}}"), avaliados a cada requisição por NewArgs; veja parseGenerator.
+type Payload struct {
+	Args      interface{}  // Valor enviado como argumento da chamada (sem modelos).
+	replyType reflect.Type // Tipo da resposta; nil descarta a resposta.
+	expected  interface{}  // Resposta esperada em forma JSON normalizada; nil desativa a verificação.
+
+	argsDoc  interface{}  // Documento dos argumentos com os modelos; nil sem modelos.
+	argsType reflect.Type // Tipo gerado para os argumentos.
+}
+
+// Estrutura payloadFile é o formato do arquivo de payload. Arquivos sem a chave
+// "args" são tratados inteiramente como argumentos (formato legado).
+type payloadFile struct {
+	Args     json.RawMessage `json:"args"`
+	Reply    json.RawMessage `json:"reply"`
+	Expected json.RawMessage `json:"expected"`
+}
+
+// DefaultPayload retorna o payload padrão, que corresponde ao servidor de exemplo:
+// Args{A: 5, B: 3} com verificação do resultado da multiplicação.
+func DefaultPayload() *Payload {
+	return legacyPayload(Args{A: 5, B: 3})
+}
+
+// legacyPayload monta o payload do formato original, com argumentos Args e resposta
+// Reply cujo resultado deve ser o produto dos operandos.
+func legacyPayload(args Args) *Payload {
+	return &Payload{
+		Args:      &args,
+		replyType: reflect.TypeOf(Reply{}),
+		expected:  map[string]interface{}{"Result": float64(args.A * args.B)},
+	}
+}
+
+// LoadPayload lê um arquivo de payload no formato {"args": ..., "reply": ..., "expected": ...}.
+// "reply" é um exemplo da resposta usado para gerar seu tipo e "expected" é o valor
+// esperado; ambos são opcionais. Sem eles a resposta é descartada.
+func LoadPayload(r io.Reader) (*Payload, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("payload deve ser um objeto JSON: %w", err)
+	}
+	if _, ok := keys["args"]; !ok {
+		return parseLegacyPayload(data)
+	}
+
+	var file payloadFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	p := &Payload{}
+	if err := p.setArgs(file.Args); err != nil {
+		return nil, err
+	}
+
+	// O tipo da resposta vem do exemplo ou, na falta dele, do valor esperado.
+	template := file.Reply
+	if len(template) == 0 {
+		template = file.Expected
+	}
+	if len(template) > 0 {
+		reply, err := buildValue(template, "reply")
+		if err != nil {
+			return nil, err
+		}
+		p.replyType = reflect.TypeOf(reply).Elem()
+	}
+
+	if len(file.Expected) > 0 {
+		if err := json.Unmarshal(file.Expected, &p.expected); err != nil {
+			return nil, fmt.Errorf("expected: JSON inválido: %w", err)
+		}
+	}
+	return p, nil
+}
+
+// parseLegacyPayload interpreta um arquivo sem a chave "args". Arquivos com apenas os
+// operandos A e B mantêm o comportamento original; os demais são enviados como estão.
+func parseLegacyPayload(data []byte) (*Payload, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	legacy := len(fields) > 0
+	for key := range fields {
+		if !strings.EqualFold(key, "A") && !strings.EqualFold(key, "B") {
+			legacy = false
+		}
+	}
+	if legacy {
+		var args Args
+		if err := json.Unmarshal(data, &args); err == nil {
+			return legacyPayload(args), nil
+		}
+	}
+
+	p := &Payload{}
+	if err := p.setArgs(data); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// setArgs gera o tipo dos argumentos e os preenche. Com modelos, o documento é guardado
+// para que NewArgs gere os valores de cada requisição.
+func (p *Payload) setArgs(data []byte) error {
+	doc, err := decodeDocument(data, "args")
+	if err != nil {
+		return err
+	}
+	doc, templated, err := compileTemplates(doc, "args")
+	if err != nil {
+		return err
+	}
+
+	t, err := typeOf(doc, "args")
+	if err != nil {
+		return err
+	}
+	ptr := reflect.New(t)
+	if err := fill(ptr.Elem(), doc, "args", nil); err != nil {
+		return err
+	}
+	p.Args = ptr.Interface()
+	if templated {
+		p.argsDoc, p.argsType = doc, t
+	}
+	return nil
+}
+
+// NewArgs devolve os argumentos de uma requisição, avaliando os modelos com os valores
+// de g. Sem modelos, devolve sempre o mesmo valor Args.
+func (p *Payload) NewArgs(g *Generation) interface{} {
+	if p.argsDoc == nil {
+		return p.Args
+	}
+	ptr := reflect.New(p.argsType)
+	_ = fill(ptr.Elem(), p.argsDoc, "args", g) // Os valores fixos já foram verificados em setArgs
+	return ptr.Interface()
+}
+
+// Templated indica se os argumentos têm modelos avaliados a cada requisição.
+func (p *Payload) Templated() bool {
+	return p.argsDoc != nil
+}
+
+// NewReply aloca uma resposta para uma chamada; nil indica que a resposta é descartada.
+func (p *Payload) NewReply() interface{} {
+	if p.replyType == nil {
+		return nil
+	}
+	return reflect.New(p.replyType).Interface()
+}
+
+// Expected retorna a resposta esperada em forma JSON normalizada, ou nil quando o
+// payload não define um valor esperado.
+func (p *Payload) Expected() interface{} {
+	return p.expected
+}
+
+// buildValue gera um tipo a partir de um documento JSON e devolve um ponteiro para
+// um valor desse tipo preenchido com o conteúdo do documento.
+func buildValue(data []byte, path string) (interface{}, error) {
+	doc, err := decodeDocument(data, path)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := typeOf(doc, path)
+	if err != nil {
+		return nil, err
+	}
+	ptr := reflect.New(t)
+	if err := fill(ptr.Elem(), doc, path, nil); err != nil {
+		return nil, err
+	}
+	return ptr.Interface(), nil
+}
+
+// decodeDocument decodifica um documento JSON preservando os números como json.Number.
+func decodeDocument(data []byte, path string) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // Preserva a distinção entre inteiros e números de ponto flutuante
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%s: JSON inválido: %w", path, err)
+	}
+	return doc, nil
+}
+
+// compileTemplates substitui as strings com geradores do documento por modelos e indica
+// se algum foi encontrado.
+func compileTemplates(doc interface{}, path string) (interface{}, bool, error) {
+	switch doc := doc.(type) {
+	case string:
+		t, err := parseTemplate(doc, path)
+		if err != nil || t == nil {
+			return doc, false, err
+		}
+		return t, true, nil
+	case []interface{}:
+		found := false
+		for i, item := range doc {
+			compiled, templated, err := compileTemplates(item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, false, err
+			}
+			doc[i], found = compiled, found || templated
+		}
+		return doc, found, nil
+	case map[string]interface{}:
+		found := false
+		for key, item := range doc {
+			compiled, templated, err := compileTemplates(item, path+"."+key)
+			if err != nil {
+				return nil, false, err
+			}
+			doc[key], found = compiled, found || templated
+		}
+		return doc, found, nil
+	default:
+		return doc, false, nil
+	}
+}
+
+// typeOf infere o tipo Go de um valor JSON decodificado.
+func typeOf(v interface{}, path string) (reflect.Type, error) {
+	switch v := v.(type) {
+	case bool:
+		return reflect.TypeOf(false), nil
+	case string:
+		return reflect.TypeOf(""), nil
+	case *template:
+		return v.typ(), nil
+	case json.Number:
+		if strings.ContainsAny(v.String(), ".eE") {
+			return reflect.TypeOf(float64(0)), nil
+		}
+		return reflect.TypeOf(int64(0)), nil
+	case []interface{}:
+		elem, err := elementType(v, path)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.SliceOf(elem), nil
+	case map[string]interface{}:
+		if entries, ok := mapEntries(v); ok {
+			values := make([]interface{}, 0, len(entries))
+			for _, key := range sortedKeys(entries) {
+				values = append(values, entries[key])
+			}
+			elem, err := elementType(values, path+"."+MapKey)
+			if err != nil {
+				return nil, err
+			}
+			return reflect.MapOf(reflect.TypeOf(""), elem), nil
+		}
+		return structType(v, path)
+	default:
+		return nil, fmt.Errorf("%s: valores nulos não têm tipo definido", path)
+	}
+}
+
+// elementType infere o tipo comum dos elementos de um array ou mapa. Inteiros
+// misturados com números de ponto flutuante resultam em float64.
+func elementType(values []interface{}, path string) (reflect.Type, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%s: o tipo de um array ou mapa vazio não pode ser inferido", path)
+	}
+
+	var elem reflect.Type
+	for i, value := range values {
+		t, err := typeOf(value, fmt.Sprintf("%s[%d]", path, i))
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case elem == nil || elem == t:
+			elem = t
+		case isNumber(elem) && isNumber(t):
+			elem = reflect.TypeOf(float64(0))
+		default:
+			return nil, fmt.Errorf("%s: elementos com tipos diferentes (%v e %v)", path, elem, t)
+		}
+	}
+	return elem, nil
+}
+
+// structType gera uma struct com um campo exportado para cada chave do objeto.
+func structType(object map[string]interface{}, path string) (reflect.Type, error) {
+	fields := make([]reflect.StructField, 0, len(object))
+	names := make(map[string]string, len(object))
+	for _, key := range sortedKeys(object) {
+		name, err := fieldName(key, path)
+		if err != nil {
+			return nil, err
+		}
+		if other, dup := names[name]; dup {
+			return nil, fmt.Errorf("%s: chaves %q e %q geram o mesmo campo %s", path, other, key, name)
+		}
+		names[name] = key
+
+		t, err := typeOf(object[key], path+"."+key)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, reflect.StructField{
+			Name: name,
+			Type: t,
+			Tag:  reflect.StructTag(fmt.Sprintf(`json:%q`, key)),
+		})
+	}
+	return reflect.StructOf(fields), nil
+}
+
+// fill preenche o valor v, já do tipo inferido, com o conteúdo JSON correspondente. Os
+// modelos são avaliados com os valores de g; sem g, mantêm o valor zero.
+func fill(v reflect.Value, doc interface{}, path string, g *Generation) error {
+	switch doc := doc.(type) {
+	case bool:
+		v.SetBool(doc)
+	case string:
+		v.SetString(doc)
+	case *template:
+		// Inteiros gerados em arrays ou mapas com números de ponto flutuante são convertidos
+		if g != nil {
+			v.Set(doc.eval(g).Convert(v.Type()))
+		}
+	case json.Number:
+		if v.Kind() == reflect.Float64 {
+			f, err := doc.Float64()
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			v.SetFloat(f)
+			return nil
+		}
+		i, err := doc.Int64()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		v.SetInt(i)
+	case []interface{}:
+		v.Set(reflect.MakeSlice(v.Type(), len(doc), len(doc)))
+		for i, item := range doc {
+			if err := fill(v.Index(i), item, fmt.Sprintf("%s[%d]", path, i), g); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		if entries, ok := mapEntries(doc); ok {
+			v.Set(reflect.MakeMapWithSize(v.Type(), len(entries)))
+			for _, key := range sortedKeys(entries) { // Ordem fixa: os modelos consomem a fonte aleatória em sequência
+				elem := reflect.New(v.Type().Elem()).Elem()
+				if err := fill(elem, entries[key], path+"."+key, g); err != nil {
+					return err
+				}
+				v.SetMapIndex(reflect.ValueOf(key), elem)
+			}
+			return nil
+		}
+		for i, key := range sortedKeys(doc) {
+			if err := fill(v.Field(i), doc[key], path+"."+key, g); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mapEntries reconhece um objeto marcado com "$map" e devolve suas entradas.
+func mapEntries(object map[string]interface{}) (map[string]interface{}, bool) {
+	if len(object) != 1 {
+		return nil, false
+	}
+	entries, ok := object[MapKey].(map[string]interface{})
+	return entries, ok
+}
+
+// fieldName converte uma chave JSON no nome de um campo exportado, como exigido pelo gob.
+func fieldName(key, path string) (string, error) {
+	first, size := utf8.DecodeRuneInString(key)
+	if key == "" || !unicode.IsLetter(first) {
+		return "", fmt.Errorf("%s: chave %q não pode ser usada como nome de campo", path, key)
+	}
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return "", fmt.Errorf("%s: chave %q não pode ser usada como nome de campo", path, key)
+		}
+	}
+	return string(unicode.ToUpper(first)) + key[size:], nil
+}
+
+// sortedKeys devolve as chaves de um objeto em ordem, para gerar tipos determinísticos.
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isNumber indica se o tipo é um dos tipos numéricos gerados.
+func isNumber(t reflect.Type) bool {
+	return t.Kind() == reflect.Int64 || t.Kind() == reflect.Float64
+}
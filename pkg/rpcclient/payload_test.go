@@ -0,0 +1,154 @@
+package rpcclient
+
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadPayloadTypes(t *testing.T) {
+	p, err := LoadPayload(strings.NewReader(`{
+		"args": {
+			"customer": "ana",
+			"items": [{"sku": "A-100", "quantity": 2, "price": 10.5}],
+			"weights": [1, 2.5],
+			"tags": {"$map": {"canal": "web"}},
+			"urgent": true
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := reflect.ValueOf(p.Args)
+	if args.Kind() != reflect.Pointer || args.Elem().Kind() != reflect.Struct {
+		t.Fatalf("argumentos do tipo %T, esperado ponteiro para struct", p.Args)
+	}
+	fields := []struct {
+		name string
+		tag  string
+		kind string
+	}{
+		{"Customer", "customer", "string"},
+		{"Items", "items", "[]struct { Price float64 \"json:\\\"price\\\"\"; Quantity int64 \"json:\\\"quantity\\\"\"; Sku string \"json:\\\"sku\\\"\" }"},
+		{"Tags", "tags", "map[string]string"},
+		{"Urgent", "urgent", "bool"},
+		{"Weights", "weights", "[]float64"},
+	}
+	typ := args.Elem().Type()
+	if typ.NumField() != len(fields) {
+		t.Fatalf("%d campos gerados, esperados %d", typ.NumField(), len(fields))
+	}
+	for i, want := range fields {
+		field := typ.Field(i)
+		if field.Name != want.name || field.Tag.Get("json") != want.tag || field.Type.String() != want.kind {
+			t.Errorf("campo %d = %s %s %q, esperado %s %s %q", i, field.Name, field.Type, field.Tag.Get("json"), want.name, want.kind, want.tag)
+		}
+	}
+
+	value := args.Elem()
+	if value.Field(0).String() != "ana" || value.Field(1).Index(0).Field(1).Int() != 2 ||
+		value.Field(2).MapIndex(reflect.ValueOf("canal")).String() != "web" || value.Field(4).Index(0).Float() != 1 {
+		t.Errorf("argumentos preenchidos incorretamente: %+v", value.Interface())
+	}
+
+	// Os tipos gerados devem ser aceitos pela codificação gob do net/rpc.
+	if err := gob.NewEncoder(&bytes.Buffer{}).Encode(p.Args); err != nil {
+		t.Errorf("argumentos não codificáveis em gob: %v", err)
+	}
+	if p.NewReply() != nil {
+		t.Error("payload sem reply nem expected deveria descartar a resposta")
+	}
+}
+
+func TestLoadPayloadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		message string
+	}{
+		{"não é objeto", `[1, 2]`, "objeto JSON"},
+		{"valor nulo", `{"args": {"a": null}}`, "args.a"},
+		{"array vazio", `{"args": {"a": []}}`, "vazio"},
+		{"mapa vazio", `{"args": {"a": {"$map": {}}}}`, "vazio"},
+		{"tipos misturados", `{"args": {"a": [1, "x"]}}`, "tipos diferentes"},
+		{"chave inválida", `{"args": {"1a": 1}}`, "nome de campo"},
+		{"chaves duplicadas", `{"args": {"nome": 1, "Nome": 2}}`, "mesmo campo"},
+		{"reply inválido", `{"args": {"a": 1}, "reply": {"b": null}}`, "reply.b"},
+	}
+	for _, tt := range tests {
+		_, err := LoadPayload(strings.NewReader(tt.payload))
+		if err == nil || !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("%s: erro %v, esperado contendo %q", tt.name, err, tt.message)
+		}
+	}
+}
+
+func TestLegacyPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		legacy  bool
+	}{
+		{"operandos", `{"A": 6, "B": 7}`, true},
+		{"operandos em minúsculas", `{"a": 6, "b": 7}`, true},
+		{"outros campos", `{"A": 6, "C": 7}`, false},
+	}
+	for _, tt := range tests {
+		p, err := LoadPayload(strings.NewReader(tt.payload))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		_, isArgs := p.Args.(*Args)
+		if isArgs != tt.legacy {
+			t.Errorf("%s: argumentos do tipo %T", tt.name, p.Args)
+		}
+		if !tt.legacy {
+			if p.NewReply() != nil {
+				t.Errorf("%s: resposta deveria ser descartada", tt.name)
+			}
+			continue
+		}
+
+		// O formato legado espera o produto dos operandos.
+		if _, ok := p.NewReply().(*Reply); !ok {
+			t.Errorf("%s: resposta do tipo %T, esperado *Reply", tt.name, p.NewReply())
+		}
+		if want := map[string]interface{}{"Result": float64(42)}; !reflect.DeepEqual(p.Expected(), want) {
+			t.Errorf("%s: resposta esperada %v, esperado %v", tt.name, p.Expected(), want)
+		}
+	}
+}
+
+func TestPayloadReply(t *testing.T) {
+	p, err := LoadPayload(strings.NewReader(`{
+		"args": {"id": 1},
+		"reply": {"status": "", "total": 0.0, "items": [""]},
+		"expected": {"status": "ok", "items": ["a", "b"]}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// O tipo da resposta vem do exemplo em "reply".
+	reply := reflect.ValueOf(p.NewReply()).Elem()
+	if reply.NumField() != 3 || reply.Type().Field(2).Type.Kind() != reflect.Float64 {
+		t.Fatalf("resposta do tipo %v", reply.Type())
+	}
+
+	want := map[string]interface{}{"status": "ok", "items": []interface{}{"a", "b"}}
+	if !reflect.DeepEqual(p.Expected(), want) {
+		t.Errorf("resposta esperada %v, esperado %v", p.Expected(), want)
+	}
+
+	// Sem "reply", o tipo da resposta vem do valor esperado.
+	p, err = LoadPayload(strings.NewReader(`{"args": {"id": 1}, "expected": {"status": "ok"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply := reflect.ValueOf(p.NewReply()).Elem(); reply.NumField() != 1 || reply.Type().Field(0).Name != "Status" {
+		t.Errorf("resposta do tipo %v", reply.Type())
+	}
+}
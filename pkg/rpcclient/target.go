@@ -0,0 +1,44 @@
+package rpcclient
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Redes aceitas nos endereços do transporte net/rpc.
+const (
+	NetworkTCP  = "tcp"  // Endereço "host:porta" ou "tcp://host:porta"
+	NetworkUnix = "unix" // Socket Unix "unix:///caminho/para.sock"
+)
+
+// Target é o destino de uma conexão net/rpc: a rede e o endereço passados ao dialer.
+type Target struct {
+	Network string // NetworkTCP ou NetworkUnix
+	Address string // "host:porta" em TCP ou o caminho do socket Unix
+}
+
+// ParseTarget interpreta o endereço de um servidor net/rpc. São aceitos "host:porta",
+// "tcp://host:porta" e "unix:///caminho/para.sock"; URLs http:// e https:// pertencem
+// ao transporte JSON-RPC 2.0 e não são aceitas aqui.
+func ParseTarget(serverAddress string) (Target, error) {
+	scheme, rest, found := strings.Cut(serverAddress, "://")
+	if !found {
+		scheme, rest = NetworkTCP, serverAddress
+	}
+
+	switch scheme {
+	case NetworkTCP:
+		if _, _, err := net.SplitHostPort(rest); err != nil {
+			return Target{}, fmt.Errorf("endereço TCP inválido %q: esperado host:porta", serverAddress)
+		}
+		return Target{Network: NetworkTCP, Address: rest}, nil
+	case NetworkUnix:
+		if rest == "" {
+			return Target{}, fmt.Errorf("endereço Unix inválido %q: esperado unix:///caminho/para.sock", serverAddress)
+		}
+		return Target{Network: NetworkUnix, Address: rest}, nil
+	default:
+		return Target{}, fmt.Errorf("esquema de endereço desconhecido %q (aceitos: tcp://, unix://, http://, https://)", scheme+"://")
+	}
+}
@@ -0,0 +1,60 @@
+package rpcclient
+
+import (
+	"context"
+	"net"
+	"net/rpc"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		address string
+		want    Target
+		wantErr bool
+	}{
+		{"localhost:1234", Target{NetworkTCP, "localhost:1234"}, false},
+		{"tcp://127.0.0.1:1234", Target{NetworkTCP, "127.0.0.1:1234"}, false},
+		{"[::1]:1234", Target{NetworkTCP, "[::1]:1234"}, false},
+		{"unix:///run/app.sock", Target{NetworkUnix, "/run/app.sock"}, false},
+		{"unix://app.sock", Target{NetworkUnix, "app.sock"}, false},
+		{"localhost", Target{}, true},
+		{"tcp://localhost", Target{}, true},
+		{"unix://", Target{}, true},
+		{"udp://localhost:1234", Target{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTarget(tt.address)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseTarget(%q) = %+v, %v; esperado %+v, erro %v", tt.address, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestDialUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rpc.sock")
+	listener, err := net.Listen(NetworkUnix, path)
+	if err != nil {
+		t.Skipf("sockets Unix indisponíveis: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("Arithmetic", &arithmetic{}); err != nil {
+		t.Fatal(err)
+	}
+	go server.Accept(listener)
+
+	client, err := Dial(context.Background(), "unix://"+path, Options{Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = client.Close() }()
+
+	var reply Reply
+	if err := client.Call(context.Background(), "Arithmetic.Multiply", &Args{A: 6, B: 7}, &reply); err != nil || reply.Result != 42 {
+		t.Errorf("resultado %d, erro %v; esperado 42", reply.Result, err)
+	}
+}
@@ -0,0 +1,254 @@
+package rpcclient
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Generation reúne os valores de uma requisição usados pelos geradores dos modelos do
+// payload. Rand deve ser derivada da semente do teste e do número da requisição, para
+// que uma mesma semente produza os mesmos valores.
+type Generation struct {
+	Seq    int64      // Número da requisição, a partir de 1.
+	Worker int        // Worker que envia a requisição, a partir de 1.
+	Rand   *rand.Rand // Fonte aleatória da requisição.
+}
+
+// Caracteres sorteados por randString.
+const randomChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// template é uma string dos argumentos com geradores entre chaves duplas, avaliada a
+// cada requisição. Um gerador que ocupa a string inteira (ex: "{{randInt 1 100}}")
+// produz um valor do seu próprio tipo; combinado com texto (ex: "user-{{seq}}"),
+// produz uma string.
+type template struct {
+	literals   []string    // Textos entre os geradores; sempre um a mais que os geradores.
+	generators []generator // Geradores na ordem em que aparecem.
+}
+
+// generator produz um valor de tipo fixo a cada requisição.
+type generator struct {
+	typ  reflect.Type
+	eval func(g *Generation) interface{}
+}
+
+// Tipos produzidos pelos geradores.
+var (
+	int64Type   = reflect.TypeOf(int64(0))
+	float64Type = reflect.TypeOf(float64(0))
+	stringType  = reflect.TypeOf("")
+)
+
+// parseTemplate interpreta os geradores de uma string. Devolve nil para strings sem
+// geradores, que são enviadas como estão.
+func parseTemplate(s, path string) (*template, error) {
+	if !strings.Contains(s, "{{") {
+		return nil, nil
+	}
+
+	t := &template{}
+	rest := s
+	for {
+		start := strings.Index(rest, "{{")
+		if start < 0 {
+			t.literals = append(t.literals, rest)
+			return t, nil
+		}
+		end := strings.Index(rest[start:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("%s: modelo %q sem \"}}\"", path, s)
+		}
+
+		gen, err := parseGenerator(strings.Fields(rest[start+2 : start+end]))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		t.literals = append(t.literals, rest[:start])
+		t.generators = append(t.generators, gen)
+		rest = rest[start+end+2:]
+	}
+}
+
+// typ devolve o tipo do valor produzido pelo modelo.
+func (t *template) typ() reflect.Type {
+	if len(t.generators) == 1 && t.literals[0] == "" && t.literals[1] == "" {
+		return t.generators[0].typ
+	}
+	return stringType
+}
+
+// eval avalia os geradores do modelo para uma requisição.
+func (t *template) eval(g *Generation) reflect.Value {
+	if t.typ() != stringType {
+		return reflect.ValueOf(t.generators[0].eval(g))
+	}
+	var b strings.Builder
+	for i, gen := range t.generators {
+		b.WriteString(t.literals[i])
+		fmt.Fprint(&b, gen.eval(g))
+	}
+	b.WriteString(t.literals[len(t.literals)-1])
+	return reflect.ValueOf(b.String())
+}
+
+// parseGenerator cria um gerador a partir do seu nome e argumentos:
+//   - seq [início]: número da requisição, a partir de início (padrão 1)
+//   - randInt min max: inteiro sorteado entre min e max, inclusive
+//   - randFloat min max: número sorteado entre min e max
+//   - randString n: string alfanumérica sorteada com n caracteres
+//   - uuid: UUID versão 4 sorteado
+//   - timestamp [unix|rfc3339]: horário do envio, em milissegundos (padrão), segundos ou texto RFC 3339
+//   - pick v1 v2 ...: um dos valores sorteado (inteiros, números ou strings)
+//   - worker: número do worker que envia a requisição
+func parseGenerator(fields []string) (generator, error) {
+	if len(fields) == 0 {
+		return generator{}, fmt.Errorf("gerador vazio")
+	}
+	name, args := fields[0], fields[1:]
+	usage := func(form string) error {
+		return fmt.Errorf("gerador %s: uso {{%s}}", name, form)
+	}
+
+	switch name {
+	case "seq":
+		start := int64(1)
+		if len(args) > 1 {
+			return generator{}, usage("seq [início]")
+		}
+		if len(args) == 1 {
+			var err error
+			if start, err = strconv.ParseInt(args[0], 10, 64); err != nil {
+				return generator{}, usage("seq [início]")
+			}
+		}
+		return generator{int64Type, func(g *Generation) interface{} { return start + g.Seq - 1 }}, nil
+
+	case "randInt":
+		if len(args) != 2 {
+			return generator{}, usage("randInt min max")
+		}
+		lo, errLo := strconv.ParseInt(args[0], 10, 64)
+		hi, errHi := strconv.ParseInt(args[1], 10, 64)
+		if errLo != nil || errHi != nil || hi < lo {
+			return generator{}, usage("randInt min max")
+		}
+		// O sorteio precisa da quantidade de valores do intervalo, hi-lo+1, em um int64
+		if span := hi - lo; span < 0 || span == math.MaxInt64 {
+			return generator{}, fmt.Errorf("gerador randInt: intervalo de %d a %d grande demais", lo, hi)
+		}
+		return generator{int64Type, func(g *Generation) interface{} { return lo + g.Rand.Int64N(hi-lo+1) }}, nil
+
+	case "randFloat":
+		if len(args) != 2 {
+			return generator{}, usage("randFloat min max")
+		}
+		lo, errLo := strconv.ParseFloat(args[0], 64)
+		hi, errHi := strconv.ParseFloat(args[1], 64)
+		if errLo != nil || errHi != nil || hi < lo {
+			return generator{}, usage("randFloat min max")
+		}
+		return generator{float64Type, func(g *Generation) interface{} { return lo + g.Rand.Float64()*(hi-lo) }}, nil
+
+	case "randString":
+		if len(args) != 1 {
+			return generator{}, usage("randString n")
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return generator{}, usage("randString n")
+		}
+		return generator{stringType, func(g *Generation) interface{} {
+			b := make([]byte, n)
+			for i := range b {
+				b[i] = randomChars[g.Rand.IntN(len(randomChars))]
+			}
+			return string(b)
+		}}, nil
+
+	case "uuid":
+		if len(args) != 0 {
+			return generator{}, usage("uuid")
+		}
+		return generator{stringType, func(g *Generation) interface{} { return newUUID(g.Rand) }}, nil
+
+	case "timestamp":
+		format := "ms"
+		if len(args) > 1 {
+			return generator{}, usage("timestamp [unix|rfc3339]")
+		}
+		if len(args) == 1 {
+			format = args[0]
+		}
+		switch format {
+		case "ms":
+			return generator{int64Type, func(*Generation) interface{} { return time.Now().UnixMilli() }}, nil
+		case "unix":
+			return generator{int64Type, func(*Generation) interface{} { return time.Now().Unix() }}, nil
+		case "rfc3339":
+			return generator{stringType, func(*Generation) interface{} { return time.Now().Format(time.RFC3339Nano) }}, nil
+		}
+		return generator{}, usage("timestamp [unix|rfc3339]")
+
+	case "pick":
+		if len(args) == 0 {
+			return generator{}, usage("pick v1 v2 ...")
+		}
+		values := pickValues(args)
+		return generator{values[0].Type(), func(g *Generation) interface{} {
+			return values[g.Rand.IntN(len(values))].Interface()
+		}}, nil
+
+	case "worker":
+		if len(args) != 0 {
+			return generator{}, usage("worker")
+		}
+		return generator{int64Type, func(g *Generation) interface{} { return int64(g.Worker) }}, nil
+	}
+	return generator{}, fmt.Errorf("gerador desconhecido %q (aceitos: seq, randInt, randFloat, randString, uuid, timestamp, pick, worker)", name)
+}
+
+// pickValues converte as opções de pick no tipo comum a todas: inteiros, números ou strings.
+func pickValues(args []string) []reflect.Value {
+	values := make([]reflect.Value, len(args))
+	ints, floats := true, true
+	for _, arg := range args {
+		if _, err := strconv.ParseInt(arg, 10, 64); err != nil {
+			ints = false
+		}
+		if _, err := strconv.ParseFloat(arg, 64); err != nil {
+			floats = false
+		}
+	}
+	for i, arg := range args {
+		switch {
+		case ints:
+			n, _ := strconv.ParseInt(arg, 10, 64)
+			values[i] = reflect.ValueOf(n)
+		case floats:
+			f, _ := strconv.ParseFloat(arg, 64)
+			values[i] = reflect.ValueOf(f)
+		default:
+			values[i] = reflect.ValueOf(arg)
+		}
+	}
+	return values
+}
+
+// newUUID sorteia um UUID versão 4 (RFC 4122) com a fonte aleatória da requisição.
+func newUUID(r *rand.Rand) string {
+	var b [16]byte
+	for i := 0; i < len(b); i += 8 {
+		v := r.Uint64()
+		for j := 0; j < 8; j++ {
+			b[i+j] = byte(v >> (8 * j))
+		}
+	}
+	b[6] = b[6]&0x0f | 0x40 // Versão 4
+	b[8] = b[8]&0x3f | 0x80 // Variante RFC 4122
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,115 @@
+package rpcclient
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+// newGeneration cria os valores de uma requisição com uma semente fixa.
+func newGeneration(seq int64) *Generation {
+	return &Generation{Seq: seq, Worker: 2, Rand: rand.New(rand.NewPCG(42, uint64(seq)))}
+}
+
+// generatedJSON carrega o payload e devolve os argumentos gerados para a requisição em JSON.
+func generatedJSON(t *testing.T, payload string, seq int64) string {
+	t.Helper()
+	p, err := LoadPayload(strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("LoadPayload: %v", err)
+	}
+	data, err := json.Marshal(p.NewArgs(newGeneration(seq)))
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	return string(data)
+}
+
+func TestNewArgsWidensGeneratedIntegers(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+	}{
+		{"array", `{"args": {"v": [1.5, "{{seq}}"]}}`, `{"v":[1.5,3]}`},
+		{"mapa", `{"args": {"$map": {"a": 2.5, "b": "{{seq 10}}"}}}`, `{"a":2.5,"b":12}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := generatedJSON(t, tt.payload, 3); got != tt.want {
+				t.Errorf("argumentos = %s, esperado %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	tests := []struct {
+		template string
+		check    func(v interface{}) bool
+	}{
+		{"{{seq}}", func(v interface{}) bool { return v == float64(7) }},
+		{"{{seq 100}}", func(v interface{}) bool { return v == float64(106) }},
+		{"{{worker}}", func(v interface{}) bool { return v == float64(2) }},
+		{"{{randInt -3 3}}", func(v interface{}) bool { n := v.(float64); return n >= -3 && n <= 3 && n == float64(int(n)) }},
+		{"{{randFloat 1 2}}", func(v interface{}) bool { f := v.(float64); return f >= 1 && f < 2 }},
+		{"{{randString 12}}", func(v interface{}) bool { return len(v.(string)) == 12 }},
+		{"{{uuid}}", func(v interface{}) bool { s := v.(string); return len(s) == 36 && s[14] == '4' }},
+		{"{{timestamp}}", func(v interface{}) bool { return v.(float64) > 1e12 }},
+		{"{{timestamp rfc3339}}", func(v interface{}) bool { return strings.Contains(v.(string), "T") }},
+		{"{{pick a b}}", func(v interface{}) bool { return v == "a" || v == "b" }},
+		{"{{pick 1 2.5}}", func(v interface{}) bool { return v == 1.0 || v == 2.5 }},
+		{"id-{{seq}}-{{worker}}", func(v interface{}) bool { return v == "id-7-2" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			var args map[string]interface{}
+			if err := json.Unmarshal([]byte(generatedJSON(t, `{"args": {"v": "`+tt.template+`"}}`, 7)), &args); err != nil {
+				t.Fatal(err)
+			}
+			if !tt.check(args["v"]) {
+				t.Errorf("%s gerou %v", tt.template, args["v"])
+			}
+		})
+	}
+}
+
+func TestGeneratorsAreReproducible(t *testing.T) {
+	payload := `{"args": {"id": "{{uuid}}", "n": "{{randInt 1 1000000}}", "s": "{{randString 16}}", "tags": {"$map": {"a": "{{randInt 1 9}}", "b": "{{randInt 1 9}}"}}}}`
+	if a, b := generatedJSON(t, payload, 5), generatedJSON(t, payload, 5); a != b {
+		t.Errorf("mesma semente e requisição geraram %s e %s", a, b)
+	}
+	if a, b := generatedJSON(t, payload, 5), generatedJSON(t, payload, 6); a == b {
+		t.Errorf("requisições diferentes geraram os mesmos argumentos %s", a)
+	}
+}
+
+func TestInvalidTemplates(t *testing.T) {
+	for _, template := range []string{
+		"{{nope}}",
+		"{{seq x}}",
+		"{{randInt 5 1}}",
+		"{{randInt -9223372036854775808 9223372036854775807}}",
+		"{{randFloat a 1}}",
+		"{{randString 0}}",
+		"{{uuid 1}}",
+		"{{timestamp iso}}",
+		"{{pick}}",
+		"{{seq",
+	} {
+		if _, err := LoadPayload(strings.NewReader(`{"args": {"v": "` + template + `"}}`)); err == nil {
+			t.Errorf("%s: esperado erro", template)
+		}
+	}
+}
+
+func TestPayloadWithoutTemplatesReusesArgs(t *testing.T) {
+	p, err := LoadPayload(strings.NewReader(`{"args": {"v": "{sem modelo}"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Templated() || p.NewArgs(newGeneration(1)) != p.Args {
+		t.Error("payload sem modelos deveria reutilizar Args")
+	}
+}
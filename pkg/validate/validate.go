@@ -0,0 +1,301 @@
+// Package validate implementa a validação das respostas recebidas nas chamadas RPC.
+//
+// Os validadores recebem a resposta na forma genérica do JSON (mapas, slices, float64,
+// strings, booleanos e nil), obtida com Normalize, o que permite validar respostas de
+// tipos gerados em tempo de execução. Validadores próprios podem ser registrados com
+// Register e selecionados com a especificação "custom:nome".
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Validator valida a resposta normalizada de uma chamada RPC. Um erro indica que a
+// resposta é inválida; a mensagem é contabilizada no relatório.
+type Validator interface {
+	Validate(reply interface{}) error
+}
+
+// Func adapta uma função comum à interface Validator.
+type Func func(reply interface{}) error
+
+// Validate executa a função.
+func (f Func) Validate(reply interface{}) error {
+	return f(reply)
+}
+
+// Registro dos validadores próprios, selecionados por nome com "custom:nome".
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Validator)
+)
+
+// Register registra um validador próprio com o nome informado. Deve ser chamado antes
+// do início do teste, tipicamente em uma função init.
+func Register(name string, v Validator) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = v
+}
+
+// lookup busca um validador registrado.
+func lookup(name string) (Validator, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	v, ok := registry[name]
+	return v, ok
+}
+
+// Parse cria um validador a partir de sua especificação:
+//   - "none": aceita qualquer resposta
+//   - "equals": compara com o valor esperado do payload (expected)
+//   - "equals:<json>": compara com o JSON informado
+//   - "field:<caminho>=<valor>": o campo deve ser igual ao valor (JSON ou texto)
+//   - "contains:<caminho>=<texto>": o campo deve conter o texto
+//   - "regex:<caminho>=<expressão>": o campo deve casar com a expressão regular
+//   - "range:<caminho>=<min>..<max>": o campo numérico deve estar no intervalo
+//     (qualquer um dos limites pode ser omitido)
+//   - "custom:<nome>": validador registrado com Register
+//
+// Caminhos usam pontos para campos e índices (ex: "items.0.sku"); o caminho vazio
+// se refere à resposta inteira. expected é o valor esperado do payload, ou nil.
+func Parse(spec string, expected interface{}) (Validator, error) {
+	kind, arg, _ := strings.Cut(strings.TrimSpace(spec), ":")
+	switch kind {
+	case "none":
+		return Func(func(interface{}) error { return nil }), nil
+	case "equals":
+		if arg == "" {
+			if expected == nil {
+				return nil, fmt.Errorf("validador equals exige \"expected\" no payload ou um valor (equals:<json>)")
+			}
+			return Equals(expected), nil
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(arg), &value); err != nil {
+			return nil, fmt.Errorf("validador equals: JSON inválido: %w", err)
+		}
+		return Equals(value), nil
+	case "custom":
+		v, ok := lookup(arg)
+		if !ok {
+			return nil, fmt.Errorf("validador customizado %q não registrado", arg)
+		}
+		return v, nil
+	case "field", "contains", "regex", "range":
+		path, value, found := strings.Cut(arg, "=")
+		if !found {
+			return nil, fmt.Errorf("validador %s deve estar no formato %s:<caminho>=<valor>", kind, kind)
+		}
+		return fieldValidator(kind, strings.TrimSpace(path), value)
+	default:
+		return nil, fmt.Errorf("validador desconhecido %q (aceitos: none, equals, field, contains, regex, range, custom)", kind)
+	}
+}
+
+// fieldValidator cria os validadores que inspecionam um campo da resposta.
+func fieldValidator(kind, path, value string) (Validator, error) {
+	switch kind {
+	case "field":
+		var want interface{}
+		if err := json.Unmarshal([]byte(value), &want); err != nil {
+			want = value // Valores que não são JSON são comparados como texto
+		}
+		return onField(path, func(got interface{}) error {
+			if !reflect.DeepEqual(got, want) {
+				return fmt.Errorf("campo %s: esperado %s, recebido %s", label(path), compact(want), compact(got))
+			}
+			return nil
+		}), nil
+	case "contains":
+		return onField(path, func(got interface{}) error {
+			if !strings.Contains(text(got), value) {
+				return fmt.Errorf("campo %s não contém %q", label(path), value)
+			}
+			return nil
+		}), nil
+	case "regex":
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("validador regex: expressão inválida: %w", err)
+		}
+		return onField(path, func(got interface{}) error {
+			if !re.MatchString(text(got)) {
+				return fmt.Errorf("campo %s não casa com /%s/", label(path), value)
+			}
+			return nil
+		}), nil
+	default:
+		return rangeValidator(path, value)
+	}
+}
+
+// rangeValidator cria o validador de intervalo numérico "min..max".
+func rangeValidator(path, value string) (Validator, error) {
+	low, high, found := strings.Cut(value, "..")
+	if !found {
+		return nil, fmt.Errorf("validador range deve estar no formato range:<caminho>=<min>..<max>")
+	}
+	bound := func(s string) (*float64, error) {
+		if s = strings.TrimSpace(s); s == "" {
+			return nil, nil
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("validador range: limite inválido %q", s)
+		}
+		return &v, nil
+	}
+	min, err := bound(low)
+	if err != nil {
+		return nil, err
+	}
+	max, err := bound(high)
+	if err != nil {
+		return nil, err
+	}
+
+	return onField(path, func(got interface{}) error {
+		n, ok := got.(float64)
+		if !ok {
+			return fmt.Errorf("campo %s não é numérico", label(path))
+		}
+		if (min != nil && n < *min) || (max != nil && n > *max) {
+			return fmt.Errorf("campo %s fora do intervalo %s", label(path), value)
+		}
+		return nil
+	}), nil
+}
+
+// Equals cria um validador que compara a resposta com o valor esperado. Objetos são
+// comparados campo a campo, ignorando os campos ausentes no valor esperado.
+func Equals(expected interface{}) Validator {
+	return Func(func(reply interface{}) error {
+		if !matches(expected, reply) {
+			return fmt.Errorf("resposta inesperada: esperado %s, recebido %s", compact(expected), compact(reply))
+		}
+		return nil
+	})
+}
+
+// onField aplica check ao campo indicado pelo caminho.
+func onField(path string, check func(interface{}) error) Validator {
+	return Func(func(reply interface{}) error {
+		got, ok := Field(reply, path)
+		if !ok {
+			return fmt.Errorf("campo %s ausente na resposta", label(path))
+		}
+		return check(got)
+	})
+}
+
+// Field extrai um campo da resposta normalizada pelo caminho separado por pontos.
+// Campos de objetos são buscados pelo nome exato e, na falta dele, sem diferenciar
+// maiúsculas de minúsculas; segmentos numéricos indexam arrays.
+func Field(reply interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return reply, true
+	}
+
+	current := reply
+	for _, segment := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[segment]
+			if !ok {
+				if value, ok = lookupFold(node, segment); !ok {
+					return nil, false
+				}
+			}
+			current = value
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			current = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// lookupFold busca a chave sem diferenciar maiúsculas de minúsculas, em ordem
+// determinística quando houver mais de uma correspondência.
+func lookupFold(node map[string]interface{}, key string) (interface{}, bool) {
+	keys := make([]string, 0, len(node))
+	for k := range node {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if strings.EqualFold(k, key) {
+			return node[k], true
+		}
+	}
+	return nil, false
+}
+
+// Normalize converte uma resposta para a forma genérica do JSON, usada pelos validadores.
+func Normalize(reply interface{}) (interface{}, error) {
+	data, err := json.Marshal(reply)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	err = json.Unmarshal(data, &out)
+	return out, err
+}
+
+// matches verifica se got contém expected: objetos são comparados campo a campo
+// (campos ausentes em expected são ignorados) e os demais valores, por igualdade.
+func matches(expected, got interface{}) bool {
+	want, ok := expected.(map[string]interface{})
+	if !ok {
+		return reflect.DeepEqual(expected, got)
+	}
+	have, ok := got.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for key, value := range want {
+		if !matches(value, have[key]) {
+			return false
+		}
+	}
+	return true
+}
+
+// text converte um valor em texto: strings são usadas diretamente e os demais valores
+// são formatados como JSON.
+func text(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return compact(v)
+}
+
+// compact formata um valor como JSON em uma linha.
+func compact(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// label nomeia o caminho nas mensagens de erro.
+func label(path string) string {
+	if path == "" {
+		return "(resposta)"
+	}
+	return path
+}
@@ -0,0 +1,119 @@
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// reply decodifica uma resposta de teste na forma normalizada.
+func reply(t *testing.T, doc string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(doc), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestParse(t *testing.T) {
+	Register("positivo", Func(func(reply interface{}) error {
+		if total, _ := Field(reply, "total"); total.(float64) <= 0 {
+			return errors.New("total não positivo")
+		}
+		return nil
+	}))
+
+	const order = `{"status": "ok", "total": 25.5, "customer": "ana maria", "items": [{"sku": "A-100"}]}`
+	tests := []struct {
+		spec  string
+		reply string
+		valid bool
+	}{
+		{"none", `null`, true},
+		{"equals", `{"status": "ok", "total": 1}`, true},
+		{"equals", `{"status": "erro"}`, false},
+		{`equals:{"total": 25.5}`, order, true},
+		{`equals:[1, 2]`, `[1, 2]`, true},
+		{`equals:[1, 2]`, `[1, 2, 3]`, false},
+		{"field:status=ok", order, true},
+		{"field:Status=ok", order, true},
+		{"field:total=25.5", order, true},
+		{"field:total=25", order, false},
+		{"field:items.0.sku=A-100", order, true},
+		{"field:items.1.sku=A-100", order, false},
+		{"field:ausente=1", order, false},
+		{"contains:customer=maria", order, true},
+		{"contains:customer=joão", order, false},
+		{"contains:total=25", order, true},
+		{"regex:items.0.sku=^[A-Z]-\\d+$", order, true},
+		{"regex:status=^erro", order, false},
+		{"range:total=0..100", order, true},
+		{"range:total=30..", order, false},
+		{"range:total=..25.5", order, true},
+		{"range:status=0..1", order, false},
+		{"custom:positivo", order, true},
+		{"custom:positivo", `{"total": 0}`, false},
+	}
+	expected := map[string]interface{}{"status": "ok"}
+	for _, tt := range tests {
+		v, err := Parse(tt.spec, expected)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.spec, err)
+			continue
+		}
+		if err := v.Validate(reply(t, tt.reply)); (err == nil) != tt.valid {
+			t.Errorf("%q com %s = %v, esperado válido %v", tt.spec, tt.reply, err, tt.valid)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		spec    string
+		message string
+	}{
+		{"equals", "expected"},
+		{"equals:{x", "JSON inválido"},
+		{"custom:ausente", "não registrado"},
+		{"field:status", "formato"},
+		{"regex:status=(", "expressão inválida"},
+		{"range:total=0-100", "formato"},
+		{"range:total=a..b", "limite inválido"},
+		{"schema:x", "desconhecido"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.spec, nil)
+		if err == nil || !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("Parse(%q) = %v, esperado erro contendo %q", tt.spec, err, tt.message)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	type item struct {
+		Sku      string `json:"sku"`
+		Quantity int64
+	}
+	got, err := Normalize(&struct {
+		Items []item
+		Tags  map[string]string
+	}{Items: []item{{"A-100", 2}}, Tags: map[string]string{"canal": "web"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"Items": []interface{}{map[string]interface{}{"sku": "A-100", "Quantity": float64(2)}},
+		"Tags":  map[string]interface{}{"canal": "web"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Normalize = %v, esperado %v", got, want)
+	}
+
+	if _, err := Normalize(make(chan int)); err == nil {
+		t.Error("valor não serializável deveria falhar")
+	}
+}